@@ -0,0 +1,71 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BlackoutPeriodSpec defines the period in which no scheduled run starts
+// +kubebuilder:validation:XValidation:rule="self.end > self.start",message="end must be after start"
+type BlackoutPeriodSpec struct {
+	// Start is when the blackout period begins.
+	// +kubebuilder:validation:Required
+	Start metav1.Time `json:"start"`
+
+	// End is when the blackout period ends.
+	// +kubebuilder:validation:Required
+	End metav1.Time `json:"end"`
+
+	// Reason explains the blackout period, it is reported by the schedules it defers.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// ScheduleSelector selects the DataMoverSchedules the blackout period applies to, in every namespace.
+	// Applies to all schedules when unset.
+	// +optional
+	ScheduleSelector *metav1.LabelSelector `json:"scheduleSelector,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Start",type="string",format="date-time",JSONPath=".spec.start"
+// +kubebuilder:printcolumn:name="End",type="string",format="date-time",JSONPath=".spec.end"
+// +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".spec.reason"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// BlackoutPeriod is the Schema for the blackoutperiods API.
+// It declares a freeze period in which the DataMoverSchedules of the cluster start no run.
+type BlackoutPeriod struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec BlackoutPeriodSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// BlackoutPeriodList contains a list of BlackoutPeriod
+type BlackoutPeriodList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BlackoutPeriod `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&BlackoutPeriod{}, &BlackoutPeriodList{})
+}
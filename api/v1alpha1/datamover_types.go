@@ -24,7 +24,116 @@ type ImageSpec struct {
 	PullPolicy corev1.PullPolicy `json:"pullPolicy,omitempty"`
 }
 
+// TransferMode selects the rclone operation run by the mover
+// +kubebuilder:validation:Enum=Sync;Copy;Check
+type TransferMode string
+
+const (
+	// TransferModeSync makes the destination identical to the source, deleting extra remote files
+	TransferModeSync TransferMode = "Sync"
+	// TransferModeCopy copies new and changed files without ever deleting remote files
+	TransferModeCopy TransferMode = "Copy"
+	// TransferModeCheck compares source and destination without writing anything
+	TransferModeCheck TransferMode = "Check"
+)
+
+// TransferSpec defines the rclone filtering and tuning options used by the mover
+type TransferSpec struct {
+	// Mode selects how data is moved to the destination.
+	// Sync deletes remote files missing locally, Copy never deletes remote files,
+	// Check only reports differences between source and destination.
+	// +kubebuilder:default:=Sync
+	// +optional
+	Mode TransferMode `json:"mode,omitempty"`
+
+	// Include patterns, using rclone filter syntax.
+	// When set, only files matching at least one pattern are transferred.
+	// +kubebuilder:validation:MaxItems=64
+	// +kubebuilder:validation:items:MinLength=1
+	// +optional
+	Include []string `json:"include,omitempty"`
+
+	// Exclude patterns, using rclone filter syntax.
+	// Excludes are evaluated before includes.
+	// +kubebuilder:validation:MaxItems=64
+	// +kubebuilder:validation:items:MinLength=1
+	// +optional
+	Exclude []string `json:"exclude,omitempty"`
+
+	// Only transfer files younger than this age (e.g. 12h, 7d, 2w).
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ms|s|m|h|d|w|M|y))+$`
+	// +optional
+	MaxAge string `json:"maxAge,omitempty"`
+
+	// Only transfer files smaller than this size (e.g. 500M, 2G).
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?[bBkKmMgGtTpP]?$`
+	// +optional
+	MaxSize string `json:"maxSize,omitempty"`
+
+	// Bandwidth limit, using rclone --bwlimit syntax.
+	// Either a single rate (10M, 10M:1M for upload:download, off) or a timetable
+	// of space separated [Day-]HH:MM,rate entries (e.g. "08:00,512k 19:00,10M Sat-00:00,off").
+	// +kubebuilder:validation:Pattern=`^((off|[0-9]+(\.[0-9]+)?[bBkKmMgGtTpP]?(:[0-9]+(\.[0-9]+)?[bBkKmMgGtTpP]?)?)|((Mon|Tue|Wed|Thu|Fri|Sat|Sun)-)?([01][0-9]|2[0-3]):[0-5][0-9],(off|[0-9]+(\.[0-9]+)?[bBkKmMgGtTpP]?(:[0-9]+(\.[0-9]+)?[bBkKmMgGtTpP]?)?)( ((Mon|Tue|Wed|Thu|Fri|Sat|Sun)-)?([01][0-9]|2[0-3]):[0-5][0-9],(off|[0-9]+(\.[0-9]+)?[bBkKmMgGtTpP]?(:[0-9]+(\.[0-9]+)?[bBkKmMgGtTpP]?)?))*)$`
+	// +optional
+	BandwidthLimit string `json:"bandwidthLimit,omitempty"`
+
+	// Number of file transfers to run in parallel.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=64
+	// +optional
+	Transfers *int32 `json:"transfers,omitempty"`
+
+	// Number of checkers to run in parallel.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=128
+	// +optional
+	Checkers *int32 `json:"checkers,omitempty"`
+
+	// Chunk size used for multipart uploads (e.g. 16M).
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?[bBkKmMgGtTpP]?$`
+	// +optional
+	MultipartChunkSize string `json:"multipartChunkSize,omitempty"`
+}
+
+// CloneSpec defines how the working clone of the source PVC is provisioned
+type CloneSpec struct {
+	// Storage class of the clone. Defaults to the storage class of the source PVC.
+	// When the CSI driver refuses to clone across storage classes, the controller
+	// falls back to snapshotting the source and restoring the snapshot into this class.
+	// +optional
+	StorageClassName *string `json:"storageClassName,omitempty"`
+
+	// Access modes of the clone. Defaults to the access modes of the source PVC.
+	// +kubebuilder:validation:items:Enum=ReadWriteOnce;ReadOnlyMany;ReadWriteMany;ReadWriteOncePod
+	// +optional
+	AccessModes []corev1.PersistentVolumeAccessMode `json:"accessModes,omitempty"`
+
+	// Volume mode of the clone. Defaults to the volume mode of the source PVC.
+	// The rclone mover reads files, so the resulting volume mode must be Filesystem.
+	// +kubebuilder:validation:Enum=Filesystem;Block
+	// +optional
+	VolumeMode *corev1.PersistentVolumeMode `json:"volumeMode,omitempty"`
+
+	// Volume snapshot class used when falling back to snapshot-then-restore.
+	// Defaults to the default snapshot class of the CSI driver.
+	// +optional
+	VolumeSnapshotClassName *string `json:"volumeSnapshotClassName,omitempty"`
+
+	// How long the clone is kept after a successful backup, as a local restore point.
+	// Ignored when deletePvcAfterBackup is true.
+	// +optional
+	RetainFor *metav1.Duration `json:"retainFor,omitempty"`
+
+	// Number of clones of the same source PVC kept after successful backups.
+	// Older clones are deleted once a newer one is retained.
+	// Ignored when deletePvcAfterBackup is true.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	RetainCount *int32 `json:"retainCount,omitempty"`
+}
+
 // DataMoverSpec defines the desired state of DataMover
+// +kubebuilder:validation:XValidation:rule="!(has(self.addTimestampPrefix) && self.addTimestampPrefix && has(self.transfer) && has(self.transfer.mode) && self.transfer.mode == 'Check')",message="transfer mode Check cannot be combined with addTimestampPrefix"
 type DataMoverSpec struct {
 	// The name of the source PersistentVolumeClaim (PVC) to clone.
 	// +kubebuilder:validation:Required
@@ -55,6 +164,50 @@ type DataMoverSpec struct {
 	// Container image configuration for the rclone job
 	// +optional
 	Image ImageSpec `json:"image,omitempty"`
+
+	// Filtering and tuning options for the rclone transfer
+	// +optional
+	Transfer *TransferSpec `json:"transfer,omitempty"`
+
+	// Whether to verify the uploaded data once the transfer is done.
+	// When true, the destination is compared with the clone and a SHA-256 manifest
+	// (.datamover.sha256) is written next to the backup. The DataMover only completes
+	// when every file matches. Ignored in Check mode.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=false
+	Verify bool `json:"verify,omitempty"`
+
+	// Whether to only estimate the transfer without writing to the destination.
+	// When true, the full clone and mover flow runs with rclone --dry-run and the
+	// estimated size, file count and number of changes are reported in the status.
+	// Ignored in Check mode.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=false
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// Overrides applied to the working clone of the source PVC
+	// +optional
+	Clone *CloneSpec `json:"clone,omitempty"`
+
+	// How long the mover pod may stay stuck (image pull errors, unschedulable,
+	// volume mount failures, missing configuration) before the DataMover fails.
+	// When unset, stuck pods are only reported in the MoverStuck condition.
+	// +optional
+	StuckPodGracePeriod *metav1.Duration `json:"stuckPodGracePeriod,omitempty"`
+
+	// Whether to pause the DataMover before it creates its next resource.
+	// A running mover Job is not interrupted: use the
+	// datamover.a-cup-of.coffee/cancel annotation to stop it.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// Seconds after the DataMover finished (Completed, Failed or Cancelled) before it is
+	// deleted along with its mover Job, snapshot and clone, unless the clone is kept by the
+	// retention policy. Defaults to the --default-ttl-seconds-after-finished
+	// flag of the operator; finished DataMovers are kept when neither is set.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
 }
 
 // DataMoverStatus defines the observed state of DataMover
@@ -63,6 +216,121 @@ type DataMoverStatus struct {
 	Phase string `json:"phase,omitempty"`
 	// A reference to the cloned PVC.
 	RestoredPVCName string `json:"restoredPvcName,omitempty"`
+
+	// Start and end time of each phase the DataMover went through.
+	// +listType=map
+	// +listMapKey=phase
+	// +optional
+	PhaseTimings []PhaseTiming `json:"phaseTimings,omitempty"`
+
+	// Name of the VolumeSnapshot used when the clone had to be restored from a snapshot.
+	// +optional
+	SnapshotName string `json:"snapshotName,omitempty"`
+
+	// Result of the last comparison when running in Check mode.
+	// +optional
+	CheckResult *CheckResult `json:"checkResult,omitempty"`
+
+	// Result of the last post-transfer verification.
+	// +optional
+	Verification *VerificationResult `json:"verification,omitempty"`
+
+	// Estimate of the transfer computed by a dry run.
+	// +optional
+	Estimate *TransferEstimate `json:"estimate,omitempty"`
+
+	// Clones of the source PVC kept as restore points by the retention policy.
+	// +optional
+	RetainedClones []RetainedClone `json:"retainedClones,omitempty"`
+
+	// Details of the mover failure, captured before its pod is garbage collected.
+	// +optional
+	LastError *MoverError `json:"lastError,omitempty"`
+
+	// Number of failed pods of the mover Job already reported by a JobRetrying event.
+	// +optional
+	FailedAttempts int32 `json:"failedAttempts,omitempty"`
+
+	// Conditions represent the latest available observations of the DataMover state.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// PhaseTiming records when a DataMover phase started and ended
+type PhaseTiming struct {
+	// Name of the phase.
+	Phase string `json:"phase"`
+	// Time the phase started.
+	StartTime metav1.Time `json:"startTime"`
+	// Time the phase ended. Unset while the phase is in progress.
+	// +optional
+	EndTime *metav1.Time `json:"endTime,omitempty"`
+}
+
+// CheckResult summarizes the differences found between source and destination
+type CheckResult struct {
+	// Number of files present in the source but missing on the destination.
+	MissingOnDestination int32 `json:"missingOnDestination"`
+	// Number of files present on the destination but missing in the source.
+	MissingOnSource int32 `json:"missingOnSource"`
+	// Number of files present on both sides with different content.
+	Differing int32 `json:"differing"`
+	// Number of files that could not be compared.
+	Errors int32 `json:"errors"`
+}
+
+// TransferEstimate summarizes what a transfer would do, as computed by a dry run
+type TransferEstimate struct {
+	// Total size in bytes of the files selected for transfer.
+	Bytes int64 `json:"bytes"`
+	// Number of files selected for transfer.
+	Files int64 `json:"files"`
+	// Number of files that would be copied, updated or deleted on the destination.
+	Changes int64 `json:"changes"`
+}
+
+// RetainedClone is a clone kept after a successful backup
+type RetainedClone struct {
+	// Name of the cloned PVC.
+	Name string `json:"name"`
+	// Time the clone was retained, when its backup completed.
+	RetainedAt metav1.Time `json:"retainedAt"`
+	// Time the clone expires. Unset when it is only limited by retainCount.
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+}
+
+// MoverError describes why the mover container failed
+type MoverError struct {
+	// Classified cause of the failure, e.g. AuthenticationFailed or NetworkError.
+	Reason string `json:"reason"`
+	// Exit code of the mover container.
+	// +optional
+	ExitCode int32 `json:"exitCode,omitempty"`
+	// Termination message of the mover container.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Last lines of the mover container logs.
+	// +optional
+	Logs string `json:"logs,omitempty"`
+	// Name of the pod the error was captured from.
+	// +optional
+	PodName string `json:"podName,omitempty"`
+	// Time the mover container terminated.
+	// +optional
+	FinishedAt *metav1.Time `json:"finishedAt,omitempty"`
+}
+
+// VerificationResult summarizes the post-transfer integrity verification
+type VerificationResult struct {
+	// Number of files that do not match between the clone and the destination.
+	Mismatches int32 `json:"mismatches"`
+	// Location of the SHA-256 manifest written next to the backup.
+	// It is suffixed with .failed when some files do not match.
+	// +optional
+	Manifest string `json:"manifest,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -21,32 +21,170 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// ConcurrencyPolicy describes how a scheduled run is handled while a previous run is still active
+// +kubebuilder:validation:Enum=Allow;Forbid;Replace
+type ConcurrencyPolicy string
+
+const (
+	// AllowConcurrent lets scheduled runs overlap
+	AllowConcurrent ConcurrencyPolicy = "Allow"
+	// ForbidConcurrent skips a scheduled run while the previous one is still active
+	ForbidConcurrent ConcurrencyPolicy = "Forbid"
+	// ReplaceConcurrent cancels the active runs, and deletes their clone, before starting a new one
+	ReplaceConcurrent ConcurrencyPolicy = "Replace"
+)
+
+// DataMoverTemplateMetadata holds the metadata propagated to the DataMovers created by a schedule
+type DataMoverTemplateMetadata struct {
+	// Labels added to the created DataMovers
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations added to the created DataMovers
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// DataMoverTemplateSpec describes the DataMovers created by a schedule
+type DataMoverTemplateSpec struct {
+	// Labels and annotations of the created DataMovers
+	// +optional
+	Metadata DataMoverTemplateMetadata `json:"metadata,omitempty"`
+
+	// Spec of the created DataMovers
+	// +kubebuilder:validation:Required
+	Spec DataMoverSpec `json:"spec"`
+}
+
+// WindowPolicy describes how a scheduled run due outside the backup windows or during a
+// BlackoutPeriod is handled
+// +kubebuilder:validation:Enum=Defer;Skip
+type WindowPolicy string
+
+const (
+	// DeferOutsideWindow starts the run once runs are allowed again
+	DeferOutsideWindow WindowPolicy = "Defer"
+	// SkipOutsideWindow skips the run, the schedule waits for its next run
+	SkipOutsideWindow WindowPolicy = "Skip"
+)
+
+// Weekday is a day of the week
+// +kubebuilder:validation:Enum=Monday;Tuesday;Wednesday;Thursday;Friday;Saturday;Sunday
+type Weekday string
+
+// BackupWindow is a daily time range in which scheduled runs may start
+// +kubebuilder:validation:XValidation:rule="self.start != self.end",message="start and end must differ"
+type BackupWindow struct {
+	// Start is the time of day the window opens, as HH:MM.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Start string `json:"start"`
+
+	// End is the time of day the window closes, as HH:MM.
+	// A window ending before its start closes on the next day.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	End string `json:"end"`
+
+	// Days are the days of the week the window opens on. Defaults to every day.
+	// +optional
+	Days []Weekday `json:"days,omitempty"`
+
+	// TimeZone is the IANA name of the time zone of the window. Defaults to the time zone of the schedule.
+	// +optional
+	TimeZone *string `json:"timeZone,omitempty"`
+}
+
+// ScheduleFailurePolicy describes how a schedule reacts to failed runs
+type ScheduleFailurePolicy struct {
+	// MaxRetries is the number of times a failed run is retried, as long as the retry starts
+	// before the next scheduled run. Failed runs are not retried when unset.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxRetries int32 `json:"maxRetries,omitempty"`
+
+	// RetryDelay is how long to wait after a failed run before retrying it. Defaults to 5m.
+	// +optional
+	RetryDelay *metav1.Duration `json:"retryDelay,omitempty"`
+
+	// SuspendAfterFailures suspends the schedule after this many consecutive failed runs.
+	// A run counts as failed once its last attempt failed.
+	// The schedule is never suspended when unset.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	SuspendAfterFailures *int32 `json:"suspendAfterFailures,omitempty"`
+}
+
 // DataMoverScheduleSpec defines the desired state of DataMoverSchedule
+// +kubebuilder:validation:XValidation:rule="has(self.dataMoverTemplate) || (has(self.sourcePvc) && has(self.secretName))",message="either dataMoverTemplate or sourcePvc and secretName must be set"
 type DataMoverScheduleSpec struct {
-	// Schedule defines the cron schedule for creating DataMover jobs
+	// Schedule defines the cron schedule for creating DataMover jobs.
+	// Accepts the standard cron syntax with ranges, lists and steps (e.g. "30 2 * * 1-5"),
+	// and macros such as @daily or @every 6h.
+	// An H in a field is replaced by a value hashed from the namespace and name of the schedule,
+	// optionally within a range or with a step: "H 2 * * *", "H(0-29) 3 * * *" or "H/15 * * * *".
 	// +kubebuilder:validation:Required
-	// +kubebuilder:validation:Pattern=`^(\*|([0-9]|1[0-9]|2[0-9]|3[0-9]|4[0-9]|5[0-9])|\*\/([0-9]|1[0-9]|2[0-9]|3[0-9]|4[0-9]|5[0-9])) (\*|([0-9]|1[0-9]|2[0-3])|\*\/([0-9]|1[0-9]|2[0-3])) (\*|([1-9]|1[0-9]|2[0-9]|3[0-1])|\*\/([1-9]|1[0-9]|2[0-9]|3[0-1])) (\*|([1-9]|1[0-2])|\*\/([1-9]|1[0-2])) (\*|([0-6])|\*\/([0-6]))$`
+	// +kubebuilder:validation:MinLength=1
 	Schedule string `json:"schedule"`
 
-	// SourcePvc is the name of the source PVC to clone
-	// +kubebuilder:validation:Required
-	SourcePvc string `json:"sourcePvc"`
+	// TimeZone is the IANA name of the time zone the schedule is evaluated in (e.g. Europe/Paris).
+	// Runs follow the wall clock across DST transitions. Defaults to the time zone of the operator.
+	// +optional
+	TimeZone *string `json:"timeZone,omitempty"`
 
-	// SecretName is the name of the secret containing storage credentials
-	// +kubebuilder:validation:Required
-	SecretName string `json:"secretName"`
+	// StartingDeadlineSeconds is how late a run missed while the operator was unavailable may start.
+	// Only the most recent missed run is started, older ones are recorded as skipped.
+	// When unset, the most recent missed run always starts.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	StartingDeadlineSeconds *int64 `json:"startingDeadlineSeconds,omitempty"`
+
+	// Jitter is the maximum delay of the runs after their scheduled time (e.g. 30m).
+	// Each schedule is delayed by a stable offset derived from its namespace and name, so that
+	// schedules sharing the same cron expression do not all start at once.
+	// +optional
+	Jitter *metav1.Duration `json:"jitter,omitempty"`
+
+	// Windows are the time ranges in which scheduled runs may start.
+	// Runs may start at any time when no window is set.
+	// +optional
+	Windows []BackupWindow `json:"windows,omitempty"`
+
+	// WindowPolicy specifies how to treat a scheduled run due outside the windows or during a BlackoutPeriod.
+	// Defer starts the run once runs are allowed again, Skip records it as skipped. Defaults to Defer.
+	// +kubebuilder:default:=Defer
+	// +optional
+	WindowPolicy WindowPolicy `json:"windowPolicy,omitempty"`
+
+	// DataMoverTemplate describes the DataMovers created on every run.
+	// Every DataMover field is available, and its labels and annotations are copied to the runs.
+	// When set, the deprecated sourcePvc, secretName, addTimestampPrefix, deletePvcAfterBackup,
+	// additionalEnv and image fields are ignored.
+	// +optional
+	DataMoverTemplate *DataMoverTemplateSpec `json:"dataMoverTemplate,omitempty"`
 
-	// AddTimestampPrefix when true, creates timestamped folders (YYYY-MM-DD-HHMMSS/) for organized backups
+	// SourcePvc is the name of the source PVC to clone.
+	// Deprecated: use dataMoverTemplate.spec.sourcePvc.
+	// +optional
+	SourcePvc string `json:"sourcePvc,omitempty"`
+
+	// SecretName is the name of the secret containing storage credentials.
+	// Deprecated: use dataMoverTemplate.spec.secretName.
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+
+	// AddTimestampPrefix when true, creates timestamped folders (YYYY-MM-DD-HHMMSS/) for organized backups.
+	// Deprecated: use dataMoverTemplate.spec.addTimestampPrefix.
 	// +kubebuilder:default:=false
 	// +optional
 	AddTimestampPrefix bool `json:"addTimestampPrefix,omitempty"`
 
-	// DeletePvcAfterBackup when true, automatically deletes the cloned PVC after successful backup
+	// DeletePvcAfterBackup when true, automatically deletes the cloned PVC after successful backup.
+	// Deprecated: use dataMoverTemplate.spec.deletePvcAfterBackup.
 	// +kubebuilder:default:=false
 	// +optional
 	DeletePvcAfterBackup bool `json:"deletePvcAfterBackup,omitempty"`
 
-	// AdditionalEnv allows specifying additional environment variables for the rclone job
+	// AdditionalEnv allows specifying additional environment variables for the rclone job.
+	// Deprecated: use dataMoverTemplate.spec.additionalEnv.
 	// +optional
 	AdditionalEnv []corev1.EnvVar `json:"additionalEnv,omitempty"`
 
@@ -56,6 +194,17 @@ type DataMoverScheduleSpec struct {
 	// +optional
 	Suspend bool `json:"suspend,omitempty"`
 
+	// ConcurrencyPolicy specifies how to treat a scheduled run while a previous one is still active.
+	// Allow runs them concurrently, Forbid skips the new run, Replace cancels the active runs
+	// and deletes their clone before starting the new one. Defaults to Allow.
+	// +kubebuilder:default:=Allow
+	// +optional
+	ConcurrencyPolicy ConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`
+
+	// FailurePolicy retries failed runs and suspends the schedule after consecutive failures.
+	// +optional
+	FailurePolicy *ScheduleFailurePolicy `json:"failurePolicy,omitempty"`
+
 	// SuccessfulJobsHistoryLimit is the number of successful finished jobs to retain.
 	// Value must be non-negative integer. Defaults to 3.
 	// +kubebuilder:default:=3
@@ -70,7 +219,8 @@ type DataMoverScheduleSpec struct {
 	// +optional
 	FailedJobsHistoryLimit *int32 `json:"failedJobsHistoryLimit,omitempty"`
 
-	// Container image configuration for the rclone job
+	// Container image configuration for the rclone job.
+	// Deprecated: use dataMoverTemplate.spec.image.
 	// +optional
 	Image ImageSpec `json:"image,omitempty"`
 }
@@ -85,6 +235,27 @@ type DataMoverScheduleStatus struct {
 	// +optional
 	LastSuccessfulTime *metav1.Time `json:"lastSuccessfulTime,omitempty"`
 
+	// Information when was the last time a job failed.
+	// +optional
+	LastFailureTime *metav1.Time `json:"lastFailureTime,omitempty"`
+
+	// Phase of the most recently created job, Pending until it starts.
+	// +optional
+	LastRunPhase string `json:"lastRunPhase,omitempty"`
+
+	// Information when the next job is scheduled. Unset while the schedule is suspended or invalid.
+	// +optional
+	NextScheduleTime *metav1.Time `json:"nextScheduleTime,omitempty"`
+
+	// Scheduled time of the run held back until a backup window opens or a blackout period ends.
+	// +optional
+	DeferredScheduleTime *metav1.Time `json:"deferredScheduleTime,omitempty"`
+
+	// Last value of the datamover.a-cup-of.coffee/trigger annotation acted upon.
+	// A manual run is started whenever the annotation is set to a different value.
+	// +optional
+	LastTriggerToken string `json:"lastTriggerToken,omitempty"`
+
 	// A list of pointers to currently running jobs.
 	// +optional
 	Active []corev1.ObjectReference `json:"active,omitempty"`
@@ -100,14 +271,36 @@ type DataMoverScheduleStatus struct {
 	// The number of failed jobs.
 	// +optional
 	FailedJobs int32 `json:"failedJobs,omitempty"`
+
+	// The number of scheduled runs that failed since the last successful run, counted once their last attempt failed.
+	// Reset when a run succeeds or the schedule is resumed after being suspended by its failure policy.
+	// +optional
+	ConsecutiveFailures int32 `json:"consecutiveFailures,omitempty"`
+
+	// The number of scheduled runs that did not start, because they were missed, started too late,
+	// forbidden by the concurrency policy, or due outside the windows with the Skip window policy.
+	// +optional
+	SkippedRuns int32 `json:"skippedRuns,omitempty"`
+
+	// Information when was the last time a scheduled run did not start.
+	// +optional
+	LastSkippedTime *metav1.Time `json:"lastSkippedTime,omitempty"`
+
+	// Conditions represent the latest available observations of the DataMoverSchedule state.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="Schedule",type="string",JSONPath=".spec.schedule"
+// +kubebuilder:printcolumn:name="TimeZone",type="string",JSONPath=".spec.timeZone"
 // +kubebuilder:printcolumn:name="Suspend",type="boolean",JSONPath=".spec.suspend"
 // +kubebuilder:printcolumn:name="Active",type="integer",JSONPath=".status.activeJobs"
 // +kubebuilder:printcolumn:name="Last Schedule",type="date",JSONPath=".status.lastScheduleTime"
+// +kubebuilder:printcolumn:name="Last Run",type="string",JSONPath=".status.lastRunPhase"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // DataMoverSchedule is the Schema for the datamoverschedules API
@@ -21,7 +21,9 @@ import (
 	"flag"
 	"os"
 	"path/filepath"
-	"time"
+
+	// Embed the time zone database used by DataMoverSchedule time zones
+	_ "time/tzdata"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -29,6 +31,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
@@ -66,6 +69,7 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var defaultTTLSecondsAfterFinished int
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(
 		&metricsAddr,
@@ -123,6 +127,9 @@ func main() {
 	)
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.IntVar(&defaultTTLSecondsAfterFinished, "default-ttl-seconds-after-finished", -1,
+		"Seconds after which finished DataMovers that do not set spec.ttlSecondsAfterFinished are deleted. "+
+			"Use -1 to keep them.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -261,11 +268,25 @@ func main() {
 		os.Exit(1)
 	}
 
+	clientset, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		setupLog.Error(err, "unable to create clientset")
+		os.Exit(1)
+	}
+
+	var defaultTTL *int32
+	if defaultTTLSecondsAfterFinished >= 0 {
+		ttl := int32(defaultTTLSecondsAfterFinished)
+		defaultTTL = &ttl
+	}
+
 	if err := (&controller.DataMoverReconciler{
-		Client:     mgr.GetClient(),
-		Scheme:     mgr.GetScheme(),
-		Log:        ctrl.Log.WithName("controllers").WithName("DataMover"),
-		PhaseStart: make(map[string]time.Time),
+		Client:                         mgr.GetClient(),
+		Scheme:                         mgr.GetScheme(),
+		Log:                            ctrl.Log.WithName("controllers").WithName("DataMover"),
+		APIReader:                      mgr.GetAPIReader(),
+		Clientset:                      clientset,
+		DefaultTTLSecondsAfterFinished: defaultTTL,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "DataMover")
 		os.Exit(1)
@@ -279,6 +300,13 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "DataMoverSchedule")
 		os.Exit(1)
 	}
+	if err := (&controller.RetainedCloneReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("RetainedClone"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "RetainedClone")
+		os.Exit(1)
+	}
 	// +kubebuilder:scaffold:builder
 
 	if metricsCertWatcher != nil {
@@ -1,5 +1,20 @@
 #!/bin/bash
 
+# Summary read back by the operator once the job is done
+termination_log="/dev/termination-log"
+
+# Report an error to the operator and exit with the given code, defaulting to 1.
+# rclone exit codes are propagated so the operator can classify the failure.
+fail() {
+    local message="$1" code="${2:-1}"
+    if [ "$code" -eq 0 ]; then
+        code=1
+    fi
+    echo "❌ $message"
+    jq -cn --arg error "$message" --argjson exitCode "$code" '{error: $error, exitCode: $exitCode}' > "$termination_log"
+    exit "$code"
+}
+
 echo "🚀 Starting rclone configuration..."
 rclone_version=$(rclone --version | head -n 1 | awk '{print $2}')
 echo "📦 Rclone version: $rclone_version"
@@ -9,8 +24,7 @@ export HOME="/config/"
 
 # Configure rclone s3 generic
 if [ -z "$AWS_ACCESS_KEY_ID" ] || [ -z "$AWS_SECRET_ACCESS_KEY" ]; then
-  echo "❌ AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY must be set."
-  exit 1
+  fail "AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY must be set."
 fi
 
 if [ -z "$AWS_REGION" ]; then
@@ -19,13 +33,11 @@ if [ -z "$AWS_REGION" ]; then
 fi
 
 if [ -z "$BUCKET_HOST" ]; then
-  echo "❌ BUCKET_HOST must be set."
-  exit 1
+  fail "BUCKET_HOST must be set."
 fi
 
 if [ -z "$BUCKET_NAME" ]; then
-  echo "❌ BUCKET_NAME must be set."
-  exit 1
+  fail "BUCKET_NAME must be set."
 fi
 
 if [ -z "$BUCKET_PORT" ]; then
@@ -38,8 +50,7 @@ if [ -z "$TLS_HOST" ]; then
     echo "🔒 TLS_HOST not set, using default: $TLS_HOST"
 fi
 if [ "$TLS_HOST" != "true" ] && [ "$TLS_HOST" != "false" ]; then
-    echo "❌ TLS_HOST must be 'true' or 'false'."
-    exit 1
+    fail "TLS_HOST must be 'true' or 'false'."
 fi
 
 if [ "$TLS_HOST" == "true" ]; then
@@ -57,7 +68,7 @@ rclone config create s3generic s3 \
   region "$AWS_REGION" \
   endpoint "$endpoint" \
   bucket "$BUCKET_NAME" \
-  v2_auth false > /dev/null || { echo "❌ Rclone configuration failed."; exit 1; }
+  v2_auth false > /dev/null || fail "Rclone configuration failed." $?
 
 echo "✅ Rclone configuration completed successfully."
 
@@ -71,11 +82,158 @@ else
     echo "🔄 Using root destination: $destination_path"
 fi
 
+# Build transfer options from the DataMover spec
+rclone_flags=()
+
+# Name of the SHA-256 manifest written next to the backup when verification is enabled
+manifest_name=".datamover.sha256"
+
+if [ -n "$FILTER_RULES" ] || [ "$VERIFY" == "true" ]; then
+    filter_file="/config/filter-rules.txt"
+    : > "$filter_file"
+    if [ "$VERIFY" == "true" ]; then
+        # Never upload, compare or delete the manifest of a previous verification
+        echo "- /$manifest_name" >> "$filter_file"
+        echo "- /$manifest_name.failed" >> "$filter_file"
+    fi
+    if [ -n "$FILTER_RULES" ]; then
+        printf '%s\n' "$FILTER_RULES" >> "$filter_file"
+    fi
+    rclone_flags+=(--filter-from "$filter_file")
+    echo "🧹 Filter rules:"
+    sed 's/^/    /' "$filter_file"
+fi
+
+if [ -n "$MAX_AGE" ]; then
+    rclone_flags+=(--max-age "$MAX_AGE")
+    echo "⏳ Max file age: $MAX_AGE"
+fi
+
+if [ -n "$MAX_SIZE" ]; then
+    rclone_flags+=(--max-size "$MAX_SIZE")
+    echo "📏 Max file size: $MAX_SIZE"
+fi
+
+if [ -n "$BANDWIDTH_LIMIT" ]; then
+    rclone_flags+=(--bwlimit "$BANDWIDTH_LIMIT")
+    echo "🚦 Bandwidth limit: $BANDWIDTH_LIMIT"
+fi
+
+if [ -n "$TRANSFERS" ]; then
+    rclone_flags+=(--transfers "$TRANSFERS")
+    echo "🔀 Parallel transfers: $TRANSFERS"
+fi
+
+if [ -n "$CHECKERS" ]; then
+    rclone_flags+=(--checkers "$CHECKERS")
+    echo "🔎 Parallel checkers: $CHECKERS"
+fi
+
+if [ -n "$MULTIPART_CHUNK_SIZE" ]; then
+    rclone_flags+=(--s3-chunk-size "$MULTIPART_CHUNK_SIZE")
+    echo "🧩 Multipart chunk size: $MULTIPART_CHUNK_SIZE"
+fi
+
 echo "🔍 Testing rclone connection..."
-rclone lsd s3generic:$BUCKET_NAME -vv || { echo "❌ Rclone connection test failed."; exit 1; }
+rclone lsd s3generic:$BUCKET_NAME -vv || fail "Rclone connection test failed." $?
 echo "✅ Rclone connection test succeeded."
-echo "🔄 Starting rclone sync process..."
-echo "📂 Source: /data/"
-echo "🎯 Destination: $destination_path"
-rclone sync /data/ "$destination_path" -v || { echo "❌ Rclone sync failed."; exit 1; }
-echo "🎉 Rclone sync completed successfully."
+
+transfer_mode="${TRANSFER_MODE:-Sync}"
+
+if [ "$DRY_RUN" == "true" ] && [ "$transfer_mode" != "Check" ]; then
+    echo "🧪 Dry run enabled: nothing will be written to the destination."
+    size_json=$(rclone size /data/ "${rclone_flags[@]}" --json) || fail "Rclone size failed." $?
+    estimated_bytes=$(echo "$size_json" | jq -r '.bytes')
+    estimated_files=$(echo "$size_json" | jq -r '.count')
+    echo "📦 Selected files: $estimated_files ($estimated_bytes bytes)"
+    dry_run_log="/config/dry-run.log"
+    rclone_flags+=(--dry-run --log-file "$dry_run_log")
+fi
+
+# Print the dry run log, if any, so that it ends up in the pod logs
+show_dry_run_log() {
+    if [ -n "$dry_run_log" ] && [ -f "$dry_run_log" ]; then
+        cat "$dry_run_log"
+    fi
+}
+
+case "$transfer_mode" in
+  Sync)
+    echo "🔄 Starting rclone sync process..."
+    echo "📂 Source: /data/"
+    echo "🎯 Destination: $destination_path"
+    rclone sync /data/ "$destination_path" "${rclone_flags[@]}" -v || { status=$?; show_dry_run_log; fail "Rclone sync failed." "$status"; }
+    echo "🎉 Rclone sync completed successfully."
+    ;;
+  Copy)
+    echo "📋 Starting rclone copy process (remote files are never deleted)..."
+    echo "📂 Source: /data/"
+    echo "🎯 Destination: $destination_path"
+    rclone copy /data/ "$destination_path" "${rclone_flags[@]}" -v || { status=$?; show_dry_run_log; fail "Rclone copy failed." "$status"; }
+    echo "🎉 Rclone copy completed successfully."
+    ;;
+  Check)
+    echo "🔬 Starting rclone check process (nothing will be written)..."
+    echo "📂 Source: /data/"
+    echo "🎯 Destination: $destination_path"
+    check_report="/config/check-report.txt"
+    rclone check /data/ "$destination_path" "${rclone_flags[@]}" --combined "$check_report" -v
+    check_status=$?
+    # rclone check exits with 1 when differences are found, anything else is an error
+    if [ "$check_status" -gt 1 ] || [ ! -f "$check_report" ]; then
+        fail "Rclone check failed." "$check_status"
+    fi
+    missing_on_destination=$(grep -c '^+ ' "$check_report")
+    missing_on_source=$(grep -c '^- ' "$check_report")
+    differing=$(grep -c '^\* ' "$check_report")
+    check_errors=$(grep -c '^! ' "$check_report")
+    echo "📊 Missing on destination: $missing_on_destination, missing on source: $missing_on_source, differing: $differing, errors: $check_errors"
+    printf '{"mode":"Check","missingOnDestination":%d,"missingOnSource":%d,"differing":%d,"errors":%d}' \
+        "$missing_on_destination" "$missing_on_source" "$differing" "$check_errors" > "$termination_log"
+    echo "🎉 Rclone check completed successfully."
+    exit 0
+    ;;
+  *)
+    fail "Unknown TRANSFER_MODE: $TRANSFER_MODE (expected Sync, Copy or Check)."
+    ;;
+esac
+
+if [ -n "$dry_run_log" ]; then
+    show_dry_run_log
+    estimated_changes=$(grep -cE 'Skipped (copy|delete) as --dry-run is set' "$dry_run_log")
+    echo "📊 Estimated changes: $estimated_changes"
+    printf '{"mode":"%s","estimate":{"bytes":%d,"files":%d,"changes":%d}}' \
+        "$transfer_mode" "$estimated_bytes" "$estimated_files" "$estimated_changes" > "$termination_log"
+    echo "🧪 Dry run completed successfully."
+    exit 0
+fi
+
+verification=""
+if [ "$VERIFY" == "true" ]; then
+    echo "🔐 Verifying uploaded data..."
+    verify_report="/config/verify-report.txt"
+    # One-way check: files only present on the destination are not mismatches
+    rclone check /data/ "$destination_path" "${rclone_flags[@]}" --one-way --combined "$verify_report" -v
+    verify_status=$?
+    if [ "$verify_status" -gt 1 ] || [ ! -f "$verify_report" ]; then
+        fail "Rclone verification failed." "$verify_status"
+    fi
+    mismatches=$(grep -c -v '^= ' "$verify_report")
+    echo "📊 Mismatching files: $mismatches"
+
+    # A manifest of a backup that does not match the clone must not be trusted for restores
+    upload_name="$manifest_name"
+    if [ "$mismatches" -gt 0 ]; then
+        upload_name="${manifest_name}.failed"
+    fi
+
+    echo "🧾 Writing SHA-256 manifest..."
+    rclone hashsum sha256 /data/ "${rclone_flags[@]}" --output-file /config/sha256sums || fail "Manifest generation failed." $?
+    rclone copyto /config/sha256sums "${destination_path}${upload_name}" || fail "Manifest upload failed." $?
+    manifest="${destination_path#s3generic:}${upload_name}"
+    echo "✅ Manifest written to $manifest"
+
+    verification=$(printf ',"verification":{"mismatches":%d,"manifest":"%s"}' "$mismatches" "$manifest")
+fi
+
+printf '{"mode":"%s"%s}' "$transfer_mode" "$verification" > "$termination_log"
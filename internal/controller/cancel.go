@@ -0,0 +1,185 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	datamoverv1alpha1 "a-cup-of.coffee/datamover-operator/api/v1alpha1"
+	"a-cup-of.coffee/datamover-operator/internal/metrics"
+)
+
+const (
+	// AnnotationCancel stops a running DataMover when set to "true"
+	AnnotationCancel = "datamover.a-cup-of.coffee/cancel"
+	// AnnotationReplacedBy names the scheduled run replacing a cancelled one, whose clone is then deleted
+	AnnotationReplacedBy = "datamover.a-cup-of.coffee/replaced-by"
+)
+
+const (
+	// ConditionSuspended reports whether the DataMover is paused by spec.suspend
+	ConditionSuspended = "Suspended"
+
+	ReasonSuspended = "Suspended"
+	ReasonResumed   = "Resumed"
+
+	EventSuspended = "Suspended"
+	EventResumed   = "Resumed"
+	EventCancelled = "Cancelled"
+)
+
+// isCancelRequested reports whether the cancel annotation is set on a DataMover
+func isCancelRequested(dm *datamoverv1alpha1.DataMover) bool {
+	return dm.Annotations[AnnotationCancel] == "true"
+}
+
+// replacedBy returns the name of the DataMover replacing this one, if any
+func replacedBy(dm *datamoverv1alpha1.DataMover) string {
+	return dm.Annotations[AnnotationReplacedBy]
+}
+
+// isCancellable reports whether a DataMover in the given phase can still be cancelled.
+// Once the data is moved, the DataMover is left to complete. A DataMover waiting for its
+// mover Job is only cancelled while the Job runs, see cancelDataMover.
+func isCancellable(phase string) bool {
+	switch phase {
+	case PhaseInitial, PhaseCreatingPVC, PhasePVCReady, PhaseCreatingPod:
+		return true
+	default:
+		return false
+	}
+}
+
+// holdsOnSuspend reports whether the step of the given phase creates a resource,
+// and is therefore not run while the DataMover is suspended
+func holdsOnSuspend(phase string) bool {
+	return phase == PhaseInitial || phase == PhasePVCReady
+}
+
+// checkSuspended keeps the Suspended condition in sync with spec.suspend.
+// It reports true when the step of the current phase must not run.
+func (r *DataMoverReconciler) checkSuspended(dm *datamoverv1alpha1.DataMover) bool {
+	if dm.Spec.Suspend && holdsOnSuspend(dm.Status.Phase) {
+		if meta.SetStatusCondition(&dm.Status.Conditions, metav1.Condition{
+			Type:               ConditionSuspended,
+			Status:             metav1.ConditionTrue,
+			Reason:             ReasonSuspended,
+			Message:            "The DataMover is paused by spec.suspend",
+			ObservedGeneration: dm.Generation,
+		}) {
+			r.Recorder.Event(dm, corev1.EventTypeNormal, EventSuspended, "DataMover suspended")
+		}
+		return true
+	}
+
+	if !dm.Spec.Suspend && meta.IsStatusConditionTrue(dm.Status.Conditions, ConditionSuspended) {
+		meta.SetStatusCondition(&dm.Status.Conditions, metav1.Condition{
+			Type:               ConditionSuspended,
+			Status:             metav1.ConditionFalse,
+			Reason:             ReasonResumed,
+			Message:            "The DataMover resumed",
+			ObservedGeneration: dm.Generation,
+		})
+		r.Recorder.Event(dm, corev1.EventTypeNormal, EventResumed, "DataMover resumed")
+	}
+	return false
+}
+
+// cancelDataMover stops the mover Job, removes the snapshot and, when the DataMover
+// deletes its clone after a backup or is replaced by a newer run, the clone, then moves
+// to the Cancelled phase. A mover Job that already finished wins over the cancellation,
+// its outcome is recorded by the step of the current phase as if the DataMover was not cancelled.
+// It reports whether the reconcile was handled.
+func (r *DataMoverReconciler) cancelDataMover(
+	ctx context.Context,
+	dm *datamoverv1alpha1.DataMover,
+) (ctrl.Result, bool, error) {
+	logger := log.FromContext(ctx)
+
+	job, err := r.findMoverJob(ctx, dm)
+	if err != nil {
+		return ctrl.Result{}, true, err
+	}
+	if job != nil && isJobFinished(job) {
+		logger.Info("Mover Job already finished, ignoring the cancel request", "jobName", job.Name)
+		return ctrl.Result{}, false, nil
+	}
+
+	logger.Info("Cancelling DataMover", "phase", dm.Status.Phase)
+	if job != nil {
+		if err := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil &&
+			!errors.IsNotFound(err) {
+			logger.Error(err, "Failed to delete mover Job", "jobName", job.Name)
+			metrics.RecordError("job_delete_failed", dm.Status.Phase, dm.Namespace)
+			return ctrl.Result{}, true, err
+		}
+		logger.Info("Deleted mover Job", "jobName", job.Name)
+	}
+
+	if err := r.deleteSourceSnapshot(ctx, dm); err != nil {
+		logger.Error(err, "Failed to delete source snapshot", "snapshotName", dm.Status.SnapshotName)
+		metrics.RecordError("snapshot_delete_failed", dm.Status.Phase, dm.Namespace)
+		return ctrl.Result{}, true, err
+	}
+
+	if dm.Spec.DeletePvcAfterBackup || replacedBy(dm) != "" {
+		if err := r.deleteWorkingPVC(ctx, dm); err != nil {
+			logger.Error(err, "Failed to delete cloned PVC")
+			metrics.RecordError("pvc_delete_failed", dm.Status.Phase, dm.Namespace)
+			metrics.RecordPVCCleanupOperation("failure", dm.Namespace)
+			return ctrl.Result{}, true, err
+		}
+	}
+
+	meta.RemoveStatusCondition(&dm.Status.Conditions, ConditionSuspended)
+	clearMoverStuck(dm)
+	message := "DataMover cancelled before the data was moved"
+	if replacement := replacedBy(dm); replacement != "" {
+		message = fmt.Sprintf("%s, replaced by %s", message, replacement)
+	}
+	r.afterStatusWrite(dm, func() {
+		metrics.RecordDataSyncOperation("cancelled", dm.Namespace)
+		r.Recorder.Event(dm, corev1.EventTypeNormal, EventCancelled, message)
+	})
+	setPhase(dm, PhaseCancelled)
+	return ctrl.Result{}, true, nil
+}
+
+// deleteWorkingPVC deletes the clone the mover reads from, if it was created.
+// Clones kept by the retention policy are left to it.
+func (r *DataMoverReconciler) deleteWorkingPVC(
+	ctx context.Context,
+	dm *datamoverv1alpha1.DataMover,
+) error {
+	var pvc *corev1.PersistentVolumeClaim
+	if dm.Status.RestoredPVCName != "" {
+		pvc = &corev1.PersistentVolumeClaim{}
+		key := types.NamespacedName{Name: dm.Status.RestoredPVCName, Namespace: dm.Namespace}
+		if err := r.Get(ctx, key, pvc); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+	} else {
+		var err error
+		if pvc, err = r.findChildPVC(ctx, dm, ComponentClone); err != nil || pvc == nil {
+			return err
+		}
+	}
+
+	if pvc.Labels[LabelRetained] == "true" {
+		return nil
+	}
+	if err := r.Delete(ctx, pvc); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	metrics.RecordPVCCleanupOperation("success", dm.Namespace)
+	r.Recorder.Eventf(dm, corev1.EventTypeNormal, EventCloneDeleted, "Deleted cloned PVC %s", pvc.Name)
+	return nil
+}
@@ -0,0 +1,162 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	datamoverv1alpha1 "a-cup-of.coffee/datamover-operator/api/v1alpha1"
+	"a-cup-of.coffee/datamover-operator/internal/metrics"
+)
+
+var _ = Describe("Suspend and cancel", func() {
+	It("should only cancel DataMovers that did not move the data yet", func() {
+		Expect(isCancellable(PhaseInitial)).To(BeTrue())
+		Expect(isCancellable(PhaseCreatingPod)).To(BeTrue())
+		Expect(isCancellable(PhaseCleaningUp)).To(BeFalse())
+		Expect(isCancellable(PhaseCompleted)).To(BeFalse())
+		Expect(isCancellable(PhaseCancelled)).To(BeFalse())
+	})
+
+	It("should read the cancel annotation", func() {
+		dm := &datamoverv1alpha1.DataMover{}
+		Expect(isCancelRequested(dm)).To(BeFalse())
+		dm.Annotations = map[string]string{AnnotationCancel: "true"}
+		Expect(isCancelRequested(dm)).To(BeTrue())
+	})
+
+	It("should pause before creating resources and resume when unsuspended", func() {
+		recorder := record.NewFakeRecorder(10)
+		r := &DataMoverReconciler{Recorder: recorder}
+		dm := &datamoverv1alpha1.DataMover{
+			ObjectMeta: metav1.ObjectMeta{Name: "suspended"},
+			Spec:       datamoverv1alpha1.DataMoverSpec{Suspend: true},
+			Status:     datamoverv1alpha1.DataMoverStatus{Phase: PhasePVCReady},
+		}
+
+		Expect(r.checkSuspended(dm)).To(BeTrue())
+		Expect(meta.IsStatusConditionTrue(dm.Status.Conditions, ConditionSuspended)).To(BeTrue())
+		Expect(recorder.Events).To(Receive(ContainSubstring(EventSuspended)))
+
+		// Waiting phases keep observing the resources they already created
+		dm.Status.Phase = PhaseCreatingPod
+		Expect(r.checkSuspended(dm)).To(BeFalse())
+
+		dm.Spec.Suspend = false
+		Expect(r.checkSuspended(dm)).To(BeFalse())
+		Expect(meta.IsStatusConditionFalse(dm.Status.Conditions, ConditionSuspended)).To(BeTrue())
+		Expect(recorder.Events).To(Receive(ContainSubstring(EventResumed)))
+	})
+
+	Context("with a mover Job", func() {
+		var (
+			r   *DataMoverReconciler
+			dm  *datamoverv1alpha1.DataMover
+			job *batchv1.Job
+		)
+
+		BeforeEach(func() {
+			dm = &datamoverv1alpha1.DataMover{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "backup", Namespace: "default", UID: "1234abcd",
+					Annotations: map[string]string{AnnotationCancel: "true"},
+				},
+				Status: datamoverv1alpha1.DataMoverStatus{
+					Phase:           PhaseCreatingPod,
+					RestoredPVCName: "data-cloned-1234abcd",
+				},
+			}
+			job = &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "backup-mover-1234abcd", Namespace: "default",
+					Labels: map[string]string{LabelDataMoverUID: "1234abcd", LabelComponent: ComponentMover},
+				},
+			}
+		})
+
+		reconcile := func() {
+			scheme := runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+			Expect(datamoverv1alpha1.AddToScheme(scheme)).To(Succeed())
+			r = &DataMoverReconciler{
+				Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(job).Build(),
+				Recorder: record.NewFakeRecorder(10),
+			}
+			_, err := r.reconcilePhase(context.Background(), dm)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		It("should cancel a running Job", func() {
+			job.Status.Active = 1
+			reconcile()
+
+			Expect(dm.Status.Phase).To(Equal(PhaseCancelled))
+			err := r.Get(context.Background(), client.ObjectKeyFromObject(job), &batchv1.Job{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should let a Job that already succeeded complete the DataMover", func() {
+			job.Status.Succeeded = 1
+			reconcile()
+
+			Expect(dm.Status.Phase).To(Equal(PhaseCompleted))
+			Expect(r.Get(context.Background(), client.ObjectKeyFromObject(job), &batchv1.Job{})).To(Succeed())
+		})
+	})
+	It("should report a cancellation once its status is written", func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(datamoverv1alpha1.AddToScheme(scheme)).To(Succeed())
+		dm := &datamoverv1alpha1.DataMover{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "backup", Namespace: "cancel-conflict", UID: "1234abcd",
+				Annotations: map[string]string{AnnotationCancel: "true"},
+			},
+		}
+		conflicts := 1
+		recorder := record.NewFakeRecorder(10)
+		r := &DataMoverReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(dm).WithStatusSubresource(dm).
+				WithInterceptorFuncs(interceptor.Funcs{
+					SubResourcePatch: func(ctx context.Context, c client.Client, subResourceName string,
+						obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+						if conflicts > 0 {
+							conflicts--
+							return errors.NewConflict(datamoverv1alpha1.GroupVersion.WithResource("datamovers").GroupResource(),
+								obj.GetName(), nil)
+						}
+						return c.SubResource(subResourceName).Patch(ctx, obj, patch, opts...)
+					},
+				}).Build(),
+			Recorder: recorder,
+		}
+		cancelled := metrics.DataSyncOperationsTotal.WithLabelValues("cancelled", "cancel-conflict")
+		req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(dm)}
+
+		result, err := r.Reconcile(context.Background(), req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Requeue).To(BeTrue())
+		Expect(recorder.Events).To(BeEmpty())
+		Expect(testutil.ToFloat64(cancelled)).To(BeZero())
+
+		_, err = r.Reconcile(context.Background(), req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).To(HaveLen(1))
+		Expect(recorder.Events).To(Receive(ContainSubstring(EventCancelled)))
+		Expect(testutil.ToFloat64(cancelled)).To(Equal(1.0))
+	})
+})
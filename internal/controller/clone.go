@@ -0,0 +1,316 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	datamoverv1alpha1 "a-cup-of.coffee/datamover-operator/api/v1alpha1"
+	"a-cup-of.coffee/datamover-operator/internal/metrics"
+)
+
+// cloneStorageClassName returns the storage class of the clone, honoring the DataMover override
+func cloneStorageClassName(dm *datamoverv1alpha1.DataMover, source *corev1.PersistentVolumeClaim) *string {
+	if dm.Spec.Clone != nil && dm.Spec.Clone.StorageClassName != nil {
+		return dm.Spec.Clone.StorageClassName
+	}
+	return source.Spec.StorageClassName
+}
+
+// cloneVolumeMode returns the volume mode of the clone, honoring the DataMover override
+func cloneVolumeMode(dm *datamoverv1alpha1.DataMover, source *corev1.PersistentVolumeClaim) *corev1.PersistentVolumeMode {
+	if dm.Spec.Clone != nil && dm.Spec.Clone.VolumeMode != nil {
+		return dm.Spec.Clone.VolumeMode
+	}
+	return source.Spec.VolumeMode
+}
+
+// isCrossClassClone reports whether the clone is provisioned in another storage class than its source
+func isCrossClassClone(dm *datamoverv1alpha1.DataMover, source *corev1.PersistentVolumeClaim) bool {
+	return ptrValue(cloneStorageClassName(dm, source)) != ptrValue(source.Spec.StorageClassName)
+}
+
+func ptrValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// buildClonePVC returns the working PVC populated from the given data source,
+// applying the DataMover overrides on top of the source PVC settings
+func buildClonePVC(
+	dm *datamoverv1alpha1.DataMover,
+	source *corev1.PersistentVolumeClaim,
+	name string,
+	dataSource *corev1.TypedLocalObjectReference,
+) *corev1.PersistentVolumeClaim {
+	accessModes := source.Spec.AccessModes
+	if dm.Spec.Clone != nil && len(dm.Spec.Clone.AccessModes) > 0 {
+		accessModes = dm.Spec.Clone.AccessModes
+	}
+
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: dm.Namespace,
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: accessModes,
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: source.Spec.Resources.Requests[corev1.ResourceStorage],
+				},
+			},
+			DataSource:       dataSource,
+			StorageClassName: cloneStorageClassName(dm, source),
+			VolumeMode:       cloneVolumeMode(dm, source),
+		},
+	}
+}
+
+// defaultStorageClassAnnotation marks the storage class used by PVCs that do not set one
+const defaultStorageClassAnnotation = "storageclass.kubernetes.io/is-default-class"
+
+// waitsForFirstConsumer reports whether the storage class of a PVC delays binding
+// until a pod uses it, in which case the PVC stays Pending until the Job is scheduled
+func (r *DataMoverReconciler) waitsForFirstConsumer(
+	ctx context.Context,
+	pvc *corev1.PersistentVolumeClaim,
+) (bool, error) {
+	storageClass, err := r.getStorageClass(ctx, pvc.Spec.StorageClassName)
+	if err != nil || storageClass == nil {
+		return false, err
+	}
+	return storageClass.VolumeBindingMode != nil &&
+		*storageClass.VolumeBindingMode == storagev1.VolumeBindingWaitForFirstConsumer, nil
+}
+
+// getStorageClass returns the named storage class, or the default one when no name is given.
+// It returns nil when the class does not exist or no default class is configured.
+func (r *DataMoverReconciler) getStorageClass(
+	ctx context.Context,
+	name *string,
+) (*storagev1.StorageClass, error) {
+	if name != nil {
+		if *name == "" {
+			// An explicitly empty class disables dynamic provisioning
+			return nil, nil
+		}
+		var storageClass storagev1.StorageClass
+		if err := r.Get(ctx, types.NamespacedName{Name: *name}, &storageClass); err != nil {
+			if errors.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		return &storageClass, nil
+	}
+
+	var storageClasses storagev1.StorageClassList
+	if err := r.List(ctx, &storageClasses); err != nil {
+		return nil, err
+	}
+	for i := range storageClasses.Items {
+		if storageClasses.Items[i].Annotations[defaultStorageClassAnnotation] == "true" {
+			return &storageClasses.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// uncachedReader returns the reader used for objects the manager does not cache, such as Events and Pods
+func (r *DataMoverReconciler) uncachedReader() client.Reader {
+	if r.APIReader != nil {
+		return r.APIReader
+	}
+	return r.Client
+}
+
+// fallbackToSnapshot replaces a clone refused by the CSI driver with a snapshot of the source,
+// which is later restored into the requested storage class by restoreFromSnapshot
+func (r *DataMoverReconciler) fallbackToSnapshot(
+	ctx context.Context,
+	dm *datamoverv1alpha1.DataMover,
+	pvc *corev1.PersistentVolumeClaim,
+	reason string,
+) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	logger.Info("CSI driver refused the cross-class clone, falling back to snapshot-then-restore",
+		"pvcName", pvc.Name, "reason", reason)
+	r.Recorder.Eventf(dm, corev1.EventTypeWarning, EventCloneRefused,
+		"Cloned PVC %s was refused, falling back to snapshot-then-restore: %s", pvc.Name, reason)
+
+	if err := r.Delete(ctx, pvc); err != nil && !errors.IsNotFound(err) {
+		logger.Error(err, "Failed to delete refused clone")
+		metrics.RecordError("pvc_delete_failed", PhaseCreatingPVC, dm.Namespace)
+		return ctrl.Result{}, err
+	}
+
+	snapshotName := snapshotName(dm)
+	snapshot := &snapshotv1.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      snapshotName,
+			Namespace: dm.Namespace,
+			Labels:    childLabels(dm, ComponentSnapshot),
+		},
+		Spec: snapshotv1.VolumeSnapshotSpec{
+			Source: snapshotv1.VolumeSnapshotSource{
+				PersistentVolumeClaimName: &dm.Spec.SourcePVC,
+			},
+		},
+	}
+	if dm.Spec.Clone != nil {
+		snapshot.Spec.VolumeSnapshotClassName = dm.Spec.Clone.VolumeSnapshotClassName
+	}
+
+	if err := r.Create(ctx, snapshot); err != nil && !errors.IsAlreadyExists(err) {
+		logger.Error(err, "Failed to create source snapshot")
+		metrics.RecordError("snapshot_creation_failed", PhaseCreatingPVC, dm.Namespace)
+		return ctrl.Result{}, err
+	}
+
+	logger.Info("Successfully created source snapshot", "snapshotName", snapshotName)
+	r.Recorder.Eventf(dm, corev1.EventTypeNormal, EventSnapshotCreated,
+		"Created VolumeSnapshot %s of PVC %s", snapshotName, dm.Spec.SourcePVC)
+	metrics.RecordPVCCloneOperation("snapshot_fallback", dm.Namespace)
+
+	dm.Status.SnapshotName = snapshotName
+	dm.Status.RestoredPVCName = ""
+
+	return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+}
+
+// restoreFromSnapshot waits for the source snapshot to be ready and restores it into the working PVC
+func (r *DataMoverReconciler) restoreFromSnapshot(
+	ctx context.Context,
+	dm *datamoverv1alpha1.DataMover,
+) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var snapshot snapshotv1.VolumeSnapshot
+	if err := r.Get(ctx, types.NamespacedName{Name: dm.Status.SnapshotName, Namespace: dm.Namespace}, &snapshot); err != nil {
+		logger.Error(err, "Failed to get source snapshot")
+		metrics.RecordError("snapshot_get_failed", PhaseCreatingPVC, dm.Namespace)
+		return ctrl.Result{}, err
+	}
+
+	if snapshot.Status != nil && snapshot.Status.Error != nil && snapshot.Status.Error.Message != nil {
+		logger.Error(nil, "Source snapshot failed", "message", *snapshot.Status.Error.Message)
+		r.afterStatusWrite(dm, func() {
+			metrics.RecordError("snapshot_failed", PhaseCreatingPVC, dm.Namespace)
+			metrics.RecordPVCCloneOperation("failure", dm.Namespace)
+		})
+		r.failDataMover(dm, ReasonSnapshotFailed,
+			fmt.Sprintf("Snapshot %s failed: %s", snapshot.Name, *snapshot.Status.Error.Message))
+		return ctrl.Result{}, nil
+	}
+
+	if snapshot.Status == nil || snapshot.Status.ReadyToUse == nil || !*snapshot.Status.ReadyToUse {
+		logger.Info("Waiting for source snapshot to be ready...", "snapshotName", snapshot.Name)
+		return ctrl.Result{RequeueAfter: 15 * time.Second}, nil
+	}
+
+	// Adopt a restored PVC left by a reconcile interrupted before the status update
+	existing, err := r.findChildPVC(ctx, dm, ComponentRestored)
+	if err != nil {
+		logger.Error(err, "Failed to look up existing restored PVC")
+		metrics.RecordError("pvc_get_failed", PhaseCreatingPVC, dm.Namespace)
+		return ctrl.Result{}, err
+	}
+	if existing != nil {
+		logger.Info("Adopting existing restored PVC", "pvcName", existing.Name)
+		setClonedPVC(dm, existing.Name)
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	var sourcePVC corev1.PersistentVolumeClaim
+	if err := r.Get(ctx, types.NamespacedName{Name: dm.Spec.SourcePVC, Namespace: dm.Namespace}, &sourcePVC); err != nil {
+		logger.Error(err, "Failed to get source PVC to determine size")
+		metrics.RecordError("source_pvc_not_found", PhaseCreatingPVC, dm.Namespace)
+		return ctrl.Result{}, err
+	}
+
+	restoredPVCName := restoredName(dm)
+	apiGroup := snapshotv1.GroupName
+	pvc := buildClonePVC(dm, &sourcePVC, restoredPVCName, &corev1.TypedLocalObjectReference{
+		APIGroup: &apiGroup,
+		Kind:     "VolumeSnapshot",
+		Name:     snapshot.Name,
+	})
+	// The restored volume must be at least as large as the snapshot
+	if size := snapshot.Status.RestoreSize; size != nil &&
+		size.Cmp(pvc.Spec.Resources.Requests[corev1.ResourceStorage]) > 0 {
+		pvc.Spec.Resources.Requests[corev1.ResourceStorage] = *size
+	}
+	pvc.Labels = childLabels(dm, ComponentRestored)
+
+	if err := r.Create(ctx, pvc); err != nil && !errors.IsAlreadyExists(err) {
+		logger.Error(err, "Failed to restore snapshot into PVC")
+		metrics.RecordError("pvc_creation_failed", PhaseCreatingPVC, dm.Namespace)
+		metrics.RecordPVCCloneOperation("failure", dm.Namespace)
+		return ctrl.Result{}, err
+	}
+
+	logger.Info("Successfully restored snapshot into PVC", "pvcName", restoredPVCName, "snapshotName", snapshot.Name)
+	r.Recorder.Eventf(dm, corev1.EventTypeNormal, EventSnapshotRestored,
+		"Restored VolumeSnapshot %s into PVC %s", snapshot.Name, restoredPVCName)
+
+	setClonedPVC(dm, restoredPVCName)
+	return ctrl.Result{Requeue: true}, nil
+}
+
+// adoptSourceSnapshot resumes the snapshot fallback when its snapshot was created
+// by a reconcile interrupted before the status update. It reports whether a snapshot was adopted.
+func (r *DataMoverReconciler) adoptSourceSnapshot(
+	ctx context.Context,
+	dm *datamoverv1alpha1.DataMover,
+) (bool, error) {
+	var snapshot snapshotv1.VolumeSnapshot
+	if err := r.Get(ctx, types.NamespacedName{Name: snapshotName(dm), Namespace: dm.Namespace}, &snapshot); err != nil {
+		// Clusters without the snapshot CRDs never ran the fallback
+		if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	log.FromContext(ctx).Info("Adopting existing source snapshot", "snapshotName", snapshot.Name)
+	dm.Status.SnapshotName = snapshot.Name
+	dm.Status.RestoredPVCName = ""
+	return true, nil
+}
+
+// deleteSourceSnapshot removes the snapshot used to restore the clone, if any
+func (r *DataMoverReconciler) deleteSourceSnapshot(
+	ctx context.Context,
+	dm *datamoverv1alpha1.DataMover,
+) error {
+	if dm.Status.SnapshotName == "" {
+		return nil
+	}
+
+	snapshot := &snapshotv1.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      dm.Status.SnapshotName,
+			Namespace: dm.Namespace,
+		},
+	}
+	if err := r.Delete(ctx, snapshot); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	log.FromContext(ctx).Info("Deleted source snapshot", "snapshotName", dm.Status.SnapshotName)
+	return nil
+}
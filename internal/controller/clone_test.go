@@ -0,0 +1,58 @@
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	datamoverv1alpha1 "a-cup-of.coffee/datamover-operator/api/v1alpha1"
+)
+
+var _ = Describe("Clone PVC", func() {
+	sourceClass := "nfs"
+	filesystem := corev1.PersistentVolumeFilesystem
+	source := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "source", Namespace: "default"},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
+			StorageClassName: &sourceClass,
+			VolumeMode:       &filesystem,
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("5Gi")},
+			},
+		},
+	}
+	dataSource := &corev1.TypedLocalObjectReference{Kind: "PersistentVolumeClaim", Name: "source"}
+
+	It("should inherit the source settings without overrides", func() {
+		dm := &datamoverv1alpha1.DataMover{ObjectMeta: metav1.ObjectMeta{Namespace: "default"}}
+
+		pvc := buildClonePVC(dm, source, "clone", dataSource)
+		Expect(pvc.Spec.StorageClassName).To(Equal(&sourceClass))
+		Expect(pvc.Spec.AccessModes).To(Equal(source.Spec.AccessModes))
+		Expect(pvc.Spec.VolumeMode).To(Equal(&filesystem))
+		Expect(pvc.Spec.Resources.Requests.Storage().String()).To(Equal("5Gi"))
+		Expect(isCrossClassClone(dm, source)).To(BeFalse())
+	})
+
+	It("should apply the clone overrides", func() {
+		cloneClass := "fast-rwo"
+		dm := &datamoverv1alpha1.DataMover{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default"},
+			Spec: datamoverv1alpha1.DataMoverSpec{
+				Clone: &datamoverv1alpha1.CloneSpec{
+					StorageClassName: &cloneClass,
+					AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				},
+			},
+		}
+
+		pvc := buildClonePVC(dm, source, "clone", dataSource)
+		Expect(pvc.Spec.StorageClassName).To(Equal(&cloneClass))
+		Expect(pvc.Spec.AccessModes).To(ConsistOf(corev1.ReadWriteOnce))
+		Expect(pvc.Spec.DataSource).To(Equal(dataSource))
+		Expect(isCrossClassClone(dm, source)).To(BeTrue())
+	})
+})
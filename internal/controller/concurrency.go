@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	datamoverv1alpha1 "a-cup-of.coffee/datamover-operator/api/v1alpha1"
+)
+
+const (
+	// EventJobSkipped is emitted when a scheduled run is skipped by the Forbid concurrency policy
+	EventJobSkipped = "JobSkipped"
+	// EventJobReplaced is emitted when an active run is cancelled by the Replace concurrency policy
+	EventJobReplaced = "JobReplaced"
+)
+
+// concurrencyPolicy returns the concurrency policy of a schedule, defaulting to Allow
+func concurrencyPolicy(schedule *datamoverv1alpha1.DataMoverSchedule) datamoverv1alpha1.ConcurrencyPolicy {
+	if schedule.Spec.ConcurrencyPolicy == "" {
+		return datamoverv1alpha1.AllowConcurrent
+	}
+	return schedule.Spec.ConcurrencyPolicy
+}
+
+// runsToReplace returns the active runs the Replace policy cancels.
+// Runs that already moved their data are left to complete.
+func runsToReplace(active []*datamoverv1alpha1.DataMover) []*datamoverv1alpha1.DataMover {
+	var runs []*datamoverv1alpha1.DataMover
+	for _, dm := range active {
+		if isCancellable(dm.Status.Phase) && replacedBy(dm) == "" {
+			runs = append(runs, dm)
+		}
+	}
+	return runs
+}
+
+// replaceActiveRuns requests the cancellation of the active runs of a schedule.
+// The DataMover controller stops them and deletes their clone.
+func (r *DataMoverScheduleReconciler) replaceActiveRuns(
+	ctx context.Context,
+	schedule *datamoverv1alpha1.DataMoverSchedule,
+	active []*datamoverv1alpha1.DataMover,
+	replacement string,
+) error {
+	logger := log.FromContext(ctx)
+
+	for _, dm := range runsToReplace(active) {
+		patch := client.MergeFrom(dm.DeepCopy())
+		if dm.Annotations == nil {
+			dm.Annotations = map[string]string{}
+		}
+		dm.Annotations[AnnotationCancel] = "true"
+		dm.Annotations[AnnotationReplacedBy] = replacement
+		if err := r.Patch(ctx, dm, patch); err != nil {
+			if client.IgnoreNotFound(err) == nil {
+				continue
+			}
+			logger.Error(err, "unable to cancel active DataMover", "datamover", dm.Name)
+			return err
+		}
+
+		logger.Info("cancelled active DataMover", "datamover", dm.Name, "replacement", replacement)
+		r.Recorder.Eventf(schedule, corev1.EventTypeNormal, EventJobReplaced,
+			"Cancelled DataMover job %s, replaced by %s", dm.Name, replacement)
+	}
+	return nil
+}
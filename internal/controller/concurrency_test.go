@@ -0,0 +1,44 @@
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	datamoverv1alpha1 "a-cup-of.coffee/datamover-operator/api/v1alpha1"
+)
+
+var _ = Describe("Concurrency policy", func() {
+	It("should allow concurrent runs by default", func() {
+		schedule := &datamoverv1alpha1.DataMoverSchedule{}
+		Expect(concurrencyPolicy(schedule)).To(Equal(datamoverv1alpha1.AllowConcurrent))
+
+		schedule.Spec.ConcurrencyPolicy = datamoverv1alpha1.ForbidConcurrent
+		Expect(concurrencyPolicy(schedule)).To(Equal(datamoverv1alpha1.ForbidConcurrent))
+	})
+
+	It("should only replace runs that did not move the data yet", func() {
+		run := func(name, phase string, annotations map[string]string) *datamoverv1alpha1.DataMover {
+			return &datamoverv1alpha1.DataMover{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: annotations},
+				Status:     datamoverv1alpha1.DataMoverStatus{Phase: phase},
+			}
+		}
+		active := []*datamoverv1alpha1.DataMover{
+			run("uploading", PhaseCreatingPod, nil),
+			run("cleaning", PhaseCleaningUp, nil),
+			run("replaced", PhasePVCReady, map[string]string{AnnotationReplacedBy: "nightly-1700000000"}),
+		}
+
+		runs := runsToReplace(active)
+		Expect(runs).To(HaveLen(1))
+		Expect(runs[0].Name).To(Equal("uploading"))
+	})
+
+	It("should delete the clone of replaced runs", func() {
+		dm := &datamoverv1alpha1.DataMover{}
+		Expect(replacedBy(dm)).To(BeEmpty())
+		dm.Annotations = map[string]string{AnnotationReplacedBy: "nightly-1700000000"}
+		Expect(replacedBy(dm)).To(Equal("nightly-1700000000"))
+	})
+})
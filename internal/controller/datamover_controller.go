@@ -8,12 +8,17 @@ import (
 	"github.com/go-logr/logr"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	datamoverv1alpha1 "a-cup-of.coffee/datamover-operator/api/v1alpha1"
@@ -28,14 +33,61 @@ const (
 	PhaseCleaningUp  = "CleaningUp"
 	PhaseCompleted   = "Completed"
 	PhaseFailed      = "Failed"
+	PhaseCancelled   = "Cancelled"
+)
+
+const (
+	// ConditionInSync reports whether a Check run found the destination identical to the source
+	ConditionInSync = "InSync"
+
+	// ConditionVerified reports whether the uploaded data matched the clone after the transfer
+	ConditionVerified = "Verified"
+
+	ReasonNoDifferences       = "NoDifferences"
+	ReasonDriftDetected       = "DriftDetected"
+	ReasonVerificationPassed  = "VerificationPassed"
+	ReasonVerificationFailed  = "VerificationFailed"
+	ReasonVerificationMissing = "VerificationMissing"
+
+	// Event reasons emitted on phase transitions
+	EventCloneCreated     = "CloneCreated"
+	EventCloneAdopted     = "CloneAdopted"
+	EventCloneBound       = "CloneBound"
+	EventWaitingConsumer  = "WaitingForFirstConsumer"
+	EventCloneRefused     = "CloneRefused"
+	EventSnapshotCreated  = "SnapshotCreated"
+	EventSnapshotRestored = "SnapshotRestored"
+	EventJobCreated       = "JobCreated"
+	EventJobRetrying      = "JobRetrying"
+	EventCompleted        = "Completed"
+	EventCloneDeleted     = "CloneDeleted"
+
+	// ConditionFailed reports why the DataMover ended in the Failed phase
+	ConditionFailed = "Failed"
+
+	ReasonJobFailed             = "JobFailed"
+	ReasonCleanupFailed         = "CleanupFailed"
+	ReasonUnsupportedVolumeMode = "UnsupportedVolumeMode"
+	ReasonSnapshotFailed        = "SnapshotFailed"
 )
 
 // DataMoverReconciler reconciles a DataMover object
 type DataMoverReconciler struct {
 	client.Client
-	Scheme     *runtime.Scheme
-	Log        logr.Logger
-	PhaseStart map[string]time.Time // Track phase start times for metrics
+	Scheme *runtime.Scheme
+	Log    logr.Logger
+	// APIReader reads objects that are not cached by the manager, such as Events and the pods
+	// of mover Jobs, which would otherwise start a cluster-wide informer. Falls back to the client when unset.
+	APIReader client.Reader
+	// Clientset reads the logs of failed mover pods. Logs are not captured when unset.
+	Clientset kubernetes.Interface
+	Recorder  record.EventRecorder
+	// DefaultTTLSecondsAfterFinished applies to DataMovers that do not set
+	// spec.ttlSecondsAfterFinished. Finished DataMovers are kept when unset.
+	DefaultTTLSecondsAfterFinished *int32
+
+	phaseClock    phaseClock
+	statusEffects statusEffects
 }
 
 // +kubebuilder:rbac:groups=datamover.a-cup-of.coffee,resources=datamovers,verbs=get;list;watch;create;update;patch;delete
@@ -43,19 +95,20 @@ type DataMoverReconciler struct {
 // +kubebuilder:rbac:groups=datamover.a-cup-of.coffee,resources=datamovers/finalizers,verbs=update
 // +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=pods/log,verbs=get
 // +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;patch
+// +kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
+// +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get;list;watch;create;delete
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
 func (r *DataMoverReconciler) Reconcile(
 	ctx context.Context,
 	req ctrl.Request,
 ) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
 
-	// Initialize phase tracking map if needed
-	if r.PhaseStart == nil {
-		r.PhaseStart = make(map[string]time.Time)
-	}
-
 	// 1. Get the DataMover instance
 	var dataMover datamoverv1alpha1.DataMover
 	if err := r.Get(ctx, req.NamespacedName, &dataMover); err != nil {
@@ -63,8 +116,8 @@ func (r *DataMoverReconciler) Reconcile(
 			logger.Info("DataMover resource not found. Ignoring since object must be deleted.")
 			// Clean up metrics for deleted resource
 			metrics.DataMoverCurrentPhase.DeleteLabelValues(req.Name, req.Namespace)
-			//nolint:staticcheck // QF1008: Keeping explicit field name for clarity
-			delete(r.PhaseStart, req.NamespacedName.String())
+			metrics.DataMoverCheckDifferences.DeleteLabelValues(req.Name, req.Namespace)
+			r.phaseClock.forget(req.NamespacedName)
 			return ctrl.Result{}, nil
 		}
 		logger.Error(err, "Failed to get DataMover")
@@ -79,66 +132,129 @@ func (r *DataMoverReconciler) Reconcile(
 		metrics.GetPhaseMetricValue(dataMover.Status.Phase),
 	)
 
+	// Steps only mutate the status, it is written once the step returned
+	original := dataMover.DeepCopy()
+	result, err := r.reconcilePhase(ctx, &dataMover)
+	transitions := r.phaseClock.transitions(original, &dataMover)
+	effects := r.statusEffects.take(req.NamespacedName)
+	patched, patchErr := r.patchStatus(ctx, original, &dataMover)
+	if patchErr != nil {
+		return ctrl.Result{}, patchErr
+	}
+	if !patched {
+		if err == nil {
+			// The status changed concurrently, retry from the latest state
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return result, err
+	}
+	r.phaseClock.record(transitions)
+	for _, effect := range effects {
+		effect()
+	}
+	return result, err
+}
+
+// reconcilePhase runs the step of the current phase, updating the status in memory
+func (r *DataMoverReconciler) reconcilePhase(
+	ctx context.Context,
+	dataMover *datamoverv1alpha1.DataMover,
+) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	if isCancelRequested(dataMover) && isCancellable(dataMover.Status.Phase) {
+		if result, handled, err := r.cancelDataMover(ctx, dataMover); handled || err != nil {
+			return result, err
+		}
+	}
+	if r.checkSuspended(dataMover) {
+		logger.Info("DataMover is suspended, waiting for spec.suspend to be cleared")
+		return ctrl.Result{}, nil
+	}
+
 	// Use a switch on the current phase to manage the lifecycle
 	switch dataMover.Status.Phase {
 	case PhaseInitial:
 		// Initial phase: create cloned PVC
 		logger.Info("Phase: Creating cloned PVC")
 		metrics.RecordOperationStart(PhaseCreatingPVC, dataMover.Namespace)
-		//nolint:staticcheck // QF1008: Keeping explicit field name for clarity
-		r.PhaseStart[req.NamespacedName.String()+"-"+PhaseCreatingPVC] = time.Now()
-		return r.createClonedPVC(ctx, &dataMover)
+		return r.createClonedPVC(ctx, dataMover)
 	case PhaseCreatingPVC:
 		// Wait for PVC availability
 		logger.Info("Phase: Waiting for cloned PVC to be bound")
-		return r.waitForPVCBound(ctx, &dataMover)
+		return r.waitForPVCBound(ctx, dataMover)
 	case PhasePVCReady:
 		// PVC ready: create verification Pod
 		logger.Info("Phase: Creating verification Pod")
-		// Record PVC creation phase completion
-		//nolint:staticcheck // QF1008: Keeping explicit field name for clarity
-		if startTime, exists := r.PhaseStart[req.NamespacedName.String()+"-"+PhaseCreatingPVC]; exists {
-			duration := time.Since(startTime).Seconds()
-			metrics.RecordPhaseDuration(PhaseCreatingPVC, dataMover.Namespace, duration)
-			//nolint:staticcheck // QF1008: Keeping explicit field name for clarity
-			delete(r.PhaseStart, req.NamespacedName.String()+"-"+PhaseCreatingPVC)
-		}
 		metrics.RecordOperationSuccess(PhaseCreatingPVC, dataMover.Namespace)
 		metrics.RecordOperationStart(PhaseCreatingPod, dataMover.Namespace)
-		//nolint:staticcheck // QF1008: Keeping explicit field name for clarity
-		r.PhaseStart[req.NamespacedName.String()+"-"+PhaseCreatingPod] = time.Now()
-		return r.createVerificationJob(ctx, &dataMover)
+		return r.createVerificationJob(ctx, dataMover)
 	case PhaseCreatingPod:
 		// Wait for job to complete
 		logger.Info("Phase: Waiting for job to complete")
-		return r.waitForJobCompletion(ctx, &dataMover)
+		return r.waitForJobCompletion(ctx, dataMover)
 	case PhaseCleaningUp:
 		// Clean up cloned PVC if requested
 		logger.Info("Phase: Cleaning up cloned PVC")
-		return r.cleanupClonedPVC(ctx, &dataMover)
+		return r.cleanupClonedPVC(ctx, dataMover)
 	case PhaseCompleted:
-		// Completed, do nothing
+		// Completed: enforce the retention of kept clones, then expire after the TTL
+		if retentionEnabled(dataMover) {
+			result, err := r.sweepRetainedClones(ctx, dataMover)
+			if err != nil {
+				return result, err
+			}
+			return r.expireFinished(ctx, dataMover, result)
+		}
 		logger.Info("Phase: Completed. No more actions.")
-		return ctrl.Result{}, nil
+		return r.expireFinished(ctx, dataMover, ctrl.Result{})
 	case PhaseFailed:
-		// Failed, do nothing
+		// Failed: only expire after the TTL
 		logger.Info("Phase: Failed. No more actions.")
-		// Record failure for any ongoing phase
-		for key := range r.PhaseStart {
-			//nolint:staticcheck // QF1008: Keeping explicit field name for clarity
-			if req.NamespacedName.String() == key[:len(req.NamespacedName.String())] {
-				delete(r.PhaseStart, key)
-			}
-		}
-		metrics.RecordOperationFailure(dataMover.Status.Phase, dataMover.Namespace)
-		metrics.RecordDataSyncOperation("failure", dataMover.Namespace)
-		return ctrl.Result{}, nil
+		return r.expireFinished(ctx, dataMover, ctrl.Result{})
+	case PhaseCancelled:
+		// Cancelled: only expire after the TTL
+		logger.Info("Phase: Cancelled. No more actions.")
+		return r.expireFinished(ctx, dataMover, ctrl.Result{})
 	default:
 		logger.Info("Unknown phase, re-queuing.")
 		return ctrl.Result{Requeue: true}, nil
 	}
 }
 
+// patchStatus writes the status changes made by a step. Phase transitions use an optimistic
+// lock so that a step running on a stale object never rolls the phase back, other changes are
+// merged as is. It reports false when the patch conflicted with a concurrent update.
+func (r *DataMoverReconciler) patchStatus(
+	ctx context.Context,
+	original, dm *datamoverv1alpha1.DataMover,
+) (bool, error) {
+	if equality.Semantic.DeepEqual(original.Status, dm.Status) {
+		return true, nil
+	}
+
+	patch := client.MergeFrom(original)
+	if original.Status.Phase != dm.Status.Phase {
+		patch = client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{})
+	}
+
+	if err := r.Status().Patch(ctx, dm, patch); err != nil {
+		if errors.IsNotFound(err) {
+			// The DataMover was deleted during the reconcile, e.g. after its TTL
+			return true, nil
+		}
+		if errors.IsConflict(err) {
+			log.FromContext(ctx).V(1).Info("DataMover changed during reconcile, retrying",
+				"phase", dm.Status.Phase)
+			return false, nil
+		}
+		log.FromContext(ctx).Error(err, "Failed to patch DataMover status")
+		metrics.RecordError("status_update_failed", dm.Status.Phase, dm.Namespace)
+		return false, err
+	}
+	return true, nil
+}
+
 // --- STEP LOGIC ---
 
 func (r *DataMoverReconciler) createClonedPVC(
@@ -146,7 +262,22 @@ func (r *DataMoverReconciler) createClonedPVC(
 	dm *datamoverv1alpha1.DataMover,
 ) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
-	clonedPVCName := fmt.Sprintf("%s-cloned-%d", dm.Spec.SourcePVC, time.Now().Unix())
+	clonedPVCName := cloneName(dm)
+
+	// Adopt a clone left by a reconcile interrupted before the status update
+	existing, err := r.findChildPVC(ctx, dm, ComponentClone)
+	if err != nil {
+		logger.Error(err, "Failed to look up existing cloned PVC")
+		metrics.RecordError("pvc_get_failed", PhaseCreatingPVC, dm.Namespace)
+		return ctrl.Result{}, err
+	}
+	if existing != nil {
+		logger.Info("Adopting existing cloned PVC", "pvcName", existing.Name)
+		r.Recorder.Eventf(dm, corev1.EventTypeNormal, EventCloneAdopted,
+			"Adopted existing cloned PVC %s", existing.Name)
+		setClonedPVC(dm, existing.Name)
+		return ctrl.Result{Requeue: true}, nil
+	}
 
 	// Get the source PVC size for cloning
 	var sourcePVC corev1.PersistentVolumeClaim
@@ -156,57 +287,80 @@ func (r *DataMoverReconciler) createClonedPVC(
 		metrics.RecordPVCCloneOperation("failure", dm.Namespace)
 		return ctrl.Result{}, err
 	}
-	pvcSize := sourcePVC.Spec.Resources.Requests[corev1.ResourceStorage]
-
-	pvc := &corev1.PersistentVolumeClaim{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      clonedPVCName,
-			Namespace: dm.Namespace,
-		},
-		Spec: corev1.PersistentVolumeClaimSpec{
-			AccessModes: sourcePVC.Spec.AccessModes,
-			Resources: corev1.VolumeResourceRequirements{
-				Requests: corev1.ResourceList{
-					corev1.ResourceStorage: pvcSize,
-				},
-			},
-			DataSource: &corev1.TypedLocalObjectReference{
-				Kind: "PersistentVolumeClaim",
-				Name: dm.Spec.SourcePVC,
-			},
-			StorageClassName: sourcePVC.Spec.StorageClassName,
-		},
+	// The mover reads files from the clone, so it must be mounted as a filesystem
+	if mode := cloneVolumeMode(dm, &sourcePVC); mode != nil && *mode == corev1.PersistentVolumeBlock {
+		logger.Error(nil, "Block volume mode is not supported by the mover", "sourcePvc", dm.Spec.SourcePVC)
+		metrics.RecordError("unsupported_volume_mode", PhaseCreatingPVC, dm.Namespace)
+		metrics.RecordPVCCloneOperation("failure", dm.Namespace)
+		r.failDataMover(dm, ReasonUnsupportedVolumeMode,
+			"The clone must use the Filesystem volume mode, set spec.clone.volumeMode to Filesystem")
+		return ctrl.Result{}, nil
 	}
 
-	if err := r.Create(ctx, pvc); err != nil {
+	pvc := buildClonePVC(dm, &sourcePVC, clonedPVCName, &corev1.TypedLocalObjectReference{
+		Kind: "PersistentVolumeClaim",
+		Name: dm.Spec.SourcePVC,
+	})
+	pvc.Labels = childLabels(dm, ComponentClone)
+
+	if err := r.Create(ctx, pvc); errors.IsAlreadyExists(err) {
+		logger.Info("Adopting existing cloned PVC", "pvcName", clonedPVCName)
+		r.Recorder.Eventf(dm, corev1.EventTypeNormal, EventCloneAdopted,
+			"Adopted existing cloned PVC %s", clonedPVCName)
+		setClonedPVC(dm, clonedPVCName)
+		return ctrl.Result{Requeue: true}, nil
+	} else if err != nil {
 		logger.Error(err, "Failed to create cloned PVC")
-		metrics.RecordError("pvc_creation_failed", PhaseCreatingPVC, dm.Namespace)
 		metrics.RecordPVCCloneOperation("failure", dm.Namespace)
+		// Quota admission rejects the PVC outright, retrying will not help
+		if errors.IsForbidden(err) && classifyProvisioningFailure(err.Error()) == ReasonQuotaExceeded {
+			metrics.RecordErrorWithReason("pvc_creation_failed", metricReason(ReasonQuotaExceeded),
+				PhaseCreatingPVC, dm.Namespace)
+			r.failDataMover(dm, ReasonQuotaExceeded, err.Error())
+			return ctrl.Result{}, nil
+		}
+		metrics.RecordError("pvc_creation_failed", PhaseCreatingPVC, dm.Namespace)
 		return ctrl.Result{}, err
 	}
 
 	logger.Info("Successfully created cloned PVC", "pvcName", clonedPVCName)
 	metrics.RecordPVCCloneOperation("started", dm.Namespace)
+	r.Recorder.Eventf(dm, corev1.EventTypeNormal, EventCloneCreated,
+		"Created PVC %s cloned from %s", clonedPVCName, dm.Spec.SourcePVC)
+	r.Recorder.Eventf(&sourcePVC, corev1.EventTypeNormal, EventCloneCreated,
+		"Cloned into PVC %s by DataMover %s", clonedPVCName, dm.Name)
 
-	dm.Status.Phase = PhaseCreatingPVC
-	dm.Status.RestoredPVCName = clonedPVCName
-	if err := r.Status().Update(ctx, dm); err != nil {
-		metrics.RecordError("status_update_failed", PhaseCreatingPVC, dm.Namespace)
-		return ctrl.Result{}, err
-	}
-
+	setClonedPVC(dm, clonedPVCName)
 	return ctrl.Result{Requeue: true}, nil
 }
 
+// setClonedPVC records the working PVC in the status and moves on to waiting for it
+func setClonedPVC(dm *datamoverv1alpha1.DataMover, pvcName string) {
+	setPhase(dm, PhaseCreatingPVC)
+	dm.Status.RestoredPVCName = pvcName
+}
+
 func (r *DataMoverReconciler) waitForPVCBound(
 	ctx context.Context,
 	dm *datamoverv1alpha1.DataMover,
 ) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
+
+	// The clone was refused and the source snapshot has not been restored yet
+	if dm.Status.SnapshotName != "" && dm.Status.RestoredPVCName == "" {
+		return r.restoreFromSnapshot(ctx, dm)
+	}
+
 	var pvc corev1.PersistentVolumeClaim
 	pvcKey := types.NamespacedName{Name: dm.Status.RestoredPVCName, Namespace: dm.Namespace}
 
 	if err := r.Get(ctx, pvcKey, &pvc); err != nil {
+		// The clone may have been replaced by a snapshot before the status was updated
+		if errors.IsNotFound(err) && dm.Status.SnapshotName == "" {
+			if adopted, adoptErr := r.adoptSourceSnapshot(ctx, dm); adoptErr != nil || adopted {
+				return ctrl.Result{Requeue: adopted}, adoptErr
+			}
+		}
 		logger.Error(err, "Failed to get cloned PVC")
 		metrics.RecordError("pvc_get_failed", PhaseCreatingPVC, dm.Namespace)
 		return ctrl.Result{}, err
@@ -214,13 +368,37 @@ func (r *DataMoverReconciler) waitForPVCBound(
 
 	if pvc.Status.Phase == corev1.ClaimBound {
 		logger.Info("Cloned PVC is bound")
-		metrics.RecordPVCCloneOperation("success", dm.Namespace)
-		dm.Status.Phase = PhasePVCReady
-		if err := r.Status().Update(ctx, dm); err != nil {
-			metrics.RecordError("status_update_failed", PhasePVCReady, dm.Namespace)
+		r.afterStatusWrite(dm, func() {
+			metrics.RecordPVCCloneOperation("success", dm.Namespace)
+			r.Recorder.Eventf(dm, corev1.EventTypeNormal, EventCloneBound,
+				"Cloned PVC %s is bound", pvc.Name)
+		})
+		setPhase(dm, PhasePVCReady)
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	// With WaitForFirstConsumer the clone only binds once the Job pod is scheduled
+	if pvc.Status.Phase == corev1.ClaimPending {
+		waitForConsumer, err := r.waitsForFirstConsumer(ctx, &pvc)
+		if err != nil {
+			logger.Error(err, "Failed to get storage class of cloned PVC")
+			metrics.RecordError("storage_class_get_failed", PhaseCreatingPVC, dm.Namespace)
 			return ctrl.Result{}, err
 		}
-		return ctrl.Result{Requeue: true}, nil
+		if waitForConsumer {
+			logger.Info("Cloned PVC waits for its first consumer, creating the Job", "pvcName", pvc.Name)
+			r.afterStatusWrite(dm, func() {
+				r.Recorder.Eventf(dm, corev1.EventTypeNormal, EventWaitingConsumer,
+					"Cloned PVC %s binds once the Job pod is scheduled", pvc.Name)
+			})
+			setPhase(dm, PhasePVCReady)
+			return ctrl.Result{Requeue: true}, nil
+		}
+	}
+
+	// Provisioners report errors through ProvisioningFailed events while the PVC stays Pending
+	if result, handled, err := r.handleProvisioningFailure(ctx, dm, &pvc); handled || err != nil {
+		return result, err
 	}
 
 	logger.Info(
@@ -238,7 +416,7 @@ func (r *DataMoverReconciler) createVerificationJob(
 	dm *datamoverv1alpha1.DataMover,
 ) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
-	jobName := fmt.Sprintf("verify-%s", dm.Status.RestoredPVCName)
+	jobName := moverJobName(dm)
 
 	// Build the list of environment variables
 	envVars := make([]corev1.EnvVar, 0)
@@ -256,6 +434,25 @@ func (r *DataMoverReconciler) createVerificationJob(
 		})
 	}
 
+	// Add transfer mode, verification, filtering and tuning options
+	envVars = append(envVars, corev1.EnvVar{
+		Name:  EnvTransferMode,
+		Value: string(transferMode(dm)),
+	})
+	if verificationEnabled(dm) {
+		envVars = append(envVars, corev1.EnvVar{
+			Name:  EnvVerify,
+			Value: "true",
+		})
+	}
+	if dryRunEnabled(dm) {
+		envVars = append(envVars, corev1.EnvVar{
+			Name:  EnvDryRun,
+			Value: "true",
+		})
+	}
+	envVars = append(envVars, transferEnvVars(dm.Spec.Transfer)...)
+
 	// Add additional environment variables if specified
 	if len(dm.Spec.AdditionalEnv) > 0 {
 		envVars = append(envVars, dm.Spec.AdditionalEnv...)
@@ -286,6 +483,7 @@ func (r *DataMoverReconciler) createVerificationJob(
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      jobName,
 			Namespace: dm.Namespace,
+			Labels:    childLabels(dm, ComponentMover),
 		},
 		Spec: batchv1.JobSpec{
 			BackoffLimit: &backoffLimit,
@@ -304,6 +502,8 @@ func (r *DataMoverReconciler) createVerificationJob(
 						Name:            "rclone",
 						Image:           fullImageName,
 						ImagePullPolicy: pullPolicy,
+						// Keep the end of the logs when the mover dies before reporting its error
+						TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
 						SecurityContext: &corev1.SecurityContext{
 							AllowPrivilegeEscalation: &[]bool{false}[0],
 							RunAsNonRoot:             &[]bool{true}[0],
@@ -360,11 +560,17 @@ func (r *DataMoverReconciler) createVerificationJob(
 		},
 	}
 
+	// The Job is garbage collected with its DataMover
+	if err := controllerutil.SetControllerReference(dm, job, r.Scheme); err != nil {
+		logger.Error(err, "Failed to set controller reference on verification job")
+		metrics.RecordError("job_creation_failed", PhaseCreatingPod, dm.Namespace)
+		return ctrl.Result{}, err
+	}
+
 	// Check if the job already exists
-	foundJob := &batchv1.Job{}
-	err := r.Get(ctx, types.NamespacedName{Name: jobName, Namespace: dm.Namespace}, foundJob)
-	if err != nil && errors.IsNotFound(err) {
-		if err := r.Create(ctx, job); err != nil {
+	foundJob, err := r.findMoverJob(ctx, dm)
+	if err == nil && foundJob == nil {
+		if err := r.Create(ctx, job); err != nil && !errors.IsAlreadyExists(err) {
 			logger.Error(err, "Failed to create verification job")
 			metrics.RecordError("job_creation_failed", PhaseCreatingPod, dm.Namespace)
 			metrics.RecordPodCreationOperation("failure", dm.Namespace)
@@ -372,11 +578,9 @@ func (r *DataMoverReconciler) createVerificationJob(
 		}
 		logger.Info("Successfully created verification job", "jobName", jobName)
 		metrics.RecordPodCreationOperation("started", dm.Namespace)
-		dm.Status.Phase = PhaseCreatingPod
-		if err := r.Status().Update(ctx, dm); err != nil {
-			metrics.RecordError("status_update_failed", PhaseCreatingPod, dm.Namespace)
-			return ctrl.Result{}, err
-		}
+		r.Recorder.Eventf(dm, corev1.EventTypeNormal, EventJobCreated,
+			"Created Job %s to move data from PVC %s", jobName, dm.Status.RestoredPVCName)
+		setPhase(dm, PhaseCreatingPod)
 		return ctrl.Result{Requeue: true}, nil
 	} else if err != nil {
 		logger.Error(err, "Failed to check if job exists")
@@ -384,13 +588,20 @@ func (r *DataMoverReconciler) createVerificationJob(
 		return ctrl.Result{}, err
 	}
 
-	// If the job already exists, move to the next step
-	logger.Info("Verification job already exists", "jobName", jobName)
-	dm.Status.Phase = PhaseCreatingPod
-	if err := r.Status().Update(ctx, dm); err != nil {
-		metrics.RecordError("status_update_failed", PhaseCreatingPod, dm.Namespace)
-		return ctrl.Result{}, err
+	// If the job already exists, adopt it and move to the next step
+	logger.Info("Verification job already exists", "jobName", foundJob.Name)
+	if metav1.GetControllerOf(foundJob) == nil {
+		if err := controllerutil.SetControllerReference(dm, foundJob, r.Scheme); err != nil {
+			logger.Error(err, "Failed to set controller reference on verification job")
+			return ctrl.Result{}, err
+		}
+		if err := r.Update(ctx, foundJob); err != nil {
+			logger.Error(err, "Failed to adopt verification job", "jobName", foundJob.Name)
+			metrics.RecordError("job_update_failed", PhaseCreatingPod, dm.Namespace)
+			return ctrl.Result{}, err
+		}
 	}
+	setPhase(dm, PhaseCreatingPod)
 
 	return ctrl.Result{Requeue: true}, nil
 }
@@ -400,53 +611,97 @@ func (r *DataMoverReconciler) waitForJobCompletion(
 	dm *datamoverv1alpha1.DataMover,
 ) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
-	jobName := fmt.Sprintf("verify-%s", dm.Status.RestoredPVCName)
-	var job batchv1.Job
-	jobKey := types.NamespacedName{Name: jobName, Namespace: dm.Namespace}
 
-	if err := r.Get(ctx, jobKey, &job); err != nil {
+	job, err := r.findMoverJob(ctx, dm)
+	if err == nil && job == nil {
+		err = fmt.Errorf("verification job %s not found", moverJobName(dm))
+	}
+	if err != nil {
 		logger.Error(err, "Failed to get verification Job")
 		metrics.RecordError("job_get_failed", PhaseCreatingPod, dm.Namespace)
 		return ctrl.Result{}, err
 	}
+	jobName := job.Name
 
 	// Check if job completed successfully
 	if job.Status.Succeeded > 0 {
 		logger.Info("Verification Job completed successfully.")
-		// Record pod creation phase completion
-		if startTime, exists := r.PhaseStart[types.NamespacedName{Name: dm.Name, Namespace: dm.Namespace}.String()+"-"+PhaseCreatingPod]; exists {
-			duration := time.Since(startTime).Seconds()
-			metrics.RecordPhaseDuration(PhaseCreatingPod, dm.Namespace, duration)
-			delete(
-				r.PhaseStart,
-				types.NamespacedName{
-					Name:      dm.Name,
-					Namespace: dm.Namespace,
-				}.String()+"-"+PhaseCreatingPod,
-			)
+		clearMoverStuck(dm)
+
+		mode := transferMode(dm)
+		if mode == datamoverv1alpha1.TransferModeCheck || verificationEnabled(dm) || dryRunEnabled(dm) {
+			result, err := r.getMoverResult(ctx, dm.Namespace, jobName)
+			if err != nil {
+				logger.Error(err, "Failed to read result from verification Job")
+				metrics.RecordError("mover_result_failed", PhaseCreatingPod, dm.Namespace)
+				return ctrl.Result{}, err
+			}
+
+			if mode == datamoverv1alpha1.TransferModeCheck {
+				r.recordCheckResult(ctx, dm, result)
+			}
+
+			if dryRunEnabled(dm) {
+				dm.Status.Estimate = result.transferEstimate()
+				logger.Info("Dry run completed", "estimate", dm.Status.Estimate)
+			}
+
+			if verificationEnabled(dm) && !r.recordVerificationResult(ctx, dm, result) {
+				metrics.RecordError("verification_failed", PhaseCreatingPod, dm.Namespace)
+				r.failDataMover(dm, ReasonVerificationFailed,
+					"The uploaded data does not match the clone")
+				return ctrl.Result{}, nil
+			}
+		}
+		// The snapshot the clone was restored from is no longer needed once the data is moved
+		if err := r.deleteSourceSnapshot(ctx, dm); err != nil {
+			logger.Error(err, "Failed to delete source snapshot", "snapshotName", dm.Status.SnapshotName)
+			metrics.RecordError("snapshot_delete_failed", PhaseCreatingPod, dm.Namespace)
+			return ctrl.Result{}, err
 		}
-		metrics.RecordPodCreationOperation("success", dm.Namespace)
-		metrics.RecordDataSyncOperation("success", dm.Namespace)
+		// Kept clones are handed over to the retention policy
+		if retentionEnabled(dm) {
+			if err := r.retainClone(ctx, dm); err != nil {
+				logger.Error(err, "Failed to retain cloned PVC", "pvcName", dm.Status.RestoredPVCName)
+				metrics.RecordError("pvc_retain_failed", PhaseCreatingPod, dm.Namespace)
+				return ctrl.Result{}, err
+			}
+		}
+
+		operation := "success"
+		if dryRunEnabled(dm) {
+			operation = "dry_run"
+		}
+		var message string
+		switch {
+		case dryRunEnabled(dm) && dm.Status.Estimate != nil:
+			message = fmt.Sprintf("Dry run completed: %d files, %d bytes, %d changes",
+				dm.Status.Estimate.Files, dm.Status.Estimate.Bytes, dm.Status.Estimate.Changes)
+		case mode == datamoverv1alpha1.TransferModeCheck:
+			message = fmt.Sprintf("Check completed by Job %s", jobName)
+		default:
+			message = fmt.Sprintf("Data moved by Job %s", jobName)
+		}
+		r.afterStatusWrite(dm, func() {
+			metrics.RecordPodCreationOperation("success", dm.Namespace)
+			metrics.RecordDataSyncOperation(operation, dm.Namespace)
+			r.Recorder.Event(dm, corev1.EventTypeNormal, EventCompleted, message)
+		})
 
 		// Check if we should delete the PVC after backup
 		if dm.Spec.DeletePvcAfterBackup {
 			logger.Info("DeletePvcAfterBackup enabled, moving to cleanup phase")
-			dm.Status.Phase = PhaseCleaningUp
+			setPhase(dm, PhaseCleaningUp)
 		} else {
 			logger.Info("DeletePvcAfterBackup disabled, completing operation")
-			dm.Status.Phase = PhaseCompleted
+			setPhase(dm, PhaseCompleted)
 		}
 
-		if err := r.Status().Update(ctx, dm); err != nil {
-			metrics.RecordError("status_update_failed", dm.Status.Phase, dm.Namespace)
-			return ctrl.Result{}, err
-		}
 		return ctrl.Result{Requeue: true}, nil
 	}
 
 	// Check if job failed (reached backoff limit or has failed conditions)
-	if job.Status.Failed > 0 {
-		// Check if we reached the backoff limit
+	if isJobFailed(job) {
 		if job.Spec.BackoffLimit != nil && job.Status.Failed >= *job.Spec.BackoffLimit+1 {
 			logger.Error(nil, "Verification Job failed after all retries. DataMover process failed.",
 				"attempts", job.Status.Failed, "backoffLimit", *job.Spec.BackoffLimit)
@@ -454,16 +709,41 @@ func (r *DataMoverReconciler) waitForJobCompletion(
 			logger.Error(nil, "Verification Job failed. DataMover process failed.",
 				"attempts", job.Status.Failed)
 		}
-		metrics.RecordError("job_failed", PhaseCreatingPod, dm.Namespace)
-		metrics.RecordPodCreationOperation("failure", dm.Namespace)
-		dm.Status.Phase = PhaseFailed
-		if err := r.Status().Update(ctx, dm); err != nil {
-			metrics.RecordError("status_update_failed", PhaseFailed, dm.Namespace)
+		// Capture the failure now, the pods are deleted along with the Job
+		moverError, err := r.getMoverError(ctx, job)
+		if err != nil {
 			return ctrl.Result{}, err
 		}
+		reason := ReasonJobFailed
+		message := fmt.Sprintf("Job %s failed after %d attempts", jobName, job.Status.Failed)
+		if moverError != nil {
+			reason = moverError.Reason
+			if moverError.Message != "" {
+				message = fmt.Sprintf("%s: %s", message, moverError.Message)
+			}
+		}
+		dm.Status.LastError = moverError
+
+		r.afterStatusWrite(dm, func() {
+			metrics.RecordErrorWithReason("job_failed", metricReason(reason), PhaseCreatingPod, dm.Namespace)
+			metrics.RecordPodCreationOperation("failure", dm.Namespace)
+		})
+		r.failDataMover(dm, reason, message)
 		return ctrl.Result{}, nil
 	}
 
+	// The Job retries failed pods until it reaches its backoff limit, each failure is reported once
+	if job.Status.Failed > dm.Status.FailedAttempts {
+		dm.Status.FailedAttempts = job.Status.Failed
+		r.Recorder.Eventf(dm, corev1.EventTypeWarning, EventJobRetrying,
+			"Job %s is retrying after %d failed attempts", jobName, job.Status.Failed)
+	}
+
+	// Surface pods that cannot start instead of waiting silently
+	if result, handled, err := r.checkStuckMover(ctx, dm, job); handled || err != nil {
+		return result, err
+	}
+
 	// Job is still running or pending
 	logger.Info("Waiting for verification Job to complete...",
 		"Active", job.Status.Active,
@@ -472,6 +752,78 @@ func (r *DataMoverReconciler) waitForJobCompletion(
 	return ctrl.Result{RequeueAfter: 15 * time.Second}, nil
 }
 
+// recordCheckResult stores the differences reported by a Check run in the status and metrics
+func (r *DataMoverReconciler) recordCheckResult(
+	ctx context.Context,
+	dm *datamoverv1alpha1.DataMover,
+	result *moverResult,
+) {
+	logger := log.FromContext(ctx)
+
+	dm.Status.CheckResult = result.checkResult()
+	metrics.SetCheckDifferences(dm.Name, dm.Namespace, float64(result.differences()))
+
+	condition := metav1.Condition{
+		Type:               ConditionInSync,
+		Status:             metav1.ConditionTrue,
+		Reason:             ReasonNoDifferences,
+		Message:            "Destination matches the source",
+		ObservedGeneration: dm.Generation,
+	}
+	if result.differences() > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = ReasonDriftDetected
+		condition.Message = fmt.Sprintf(
+			"%d missing on destination, %d missing on source, %d differing, %d errors",
+			result.MissingOnDestination, result.MissingOnSource, result.Differing, result.Errors,
+		)
+	}
+	meta.SetStatusCondition(&dm.Status.Conditions, condition)
+	if condition.Status == metav1.ConditionFalse {
+		r.Recorder.Event(dm, corev1.EventTypeWarning, ReasonDriftDetected, condition.Message)
+	}
+
+	logger.Info("Check completed", "differences", result.differences(), "inSync", condition.Status)
+}
+
+// recordVerificationResult stores the outcome of the post-transfer verification
+// and returns whether the uploaded data matches the clone
+func (r *DataMoverReconciler) recordVerificationResult(
+	ctx context.Context,
+	dm *datamoverv1alpha1.DataMover,
+	result *moverResult,
+) bool {
+	logger := log.FromContext(ctx)
+
+	condition := metav1.Condition{
+		Type:               ConditionVerified,
+		ObservedGeneration: dm.Generation,
+	}
+
+	switch {
+	case result.Verification == nil:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = ReasonVerificationMissing
+		condition.Message = "The mover did not report a verification result"
+	case result.Verification.Mismatches > 0:
+		dm.Status.Verification = result.verificationResult()
+		metrics.RecordVerificationMismatches(dm.Namespace, float64(result.Verification.Mismatches))
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = ReasonVerificationFailed
+		condition.Message = fmt.Sprintf("%d files do not match the destination", result.Verification.Mismatches)
+	default:
+		dm.Status.Verification = result.verificationResult()
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = ReasonVerificationPassed
+		condition.Message = fmt.Sprintf("All files match the destination, manifest written to %s",
+			result.Verification.Manifest)
+	}
+	meta.SetStatusCondition(&dm.Status.Conditions, condition)
+
+	logger.Info("Verification completed", "verified", condition.Status, "reason", condition.Reason)
+	return condition.Status == metav1.ConditionTrue
+}
+
 func (r *DataMoverReconciler) cleanupClonedPVC(
 	ctx context.Context,
 	dm *datamoverv1alpha1.DataMover,
@@ -480,11 +832,7 @@ func (r *DataMoverReconciler) cleanupClonedPVC(
 
 	if dm.Status.RestoredPVCName == "" {
 		logger.Info("No cloned PVC to cleanup, completing operation")
-		dm.Status.Phase = PhaseCompleted
-		if err := r.Status().Update(ctx, dm); err != nil {
-			metrics.RecordError("status_update_failed", PhaseCompleted, dm.Namespace)
-			return ctrl.Result{}, err
-		}
+		setPhase(dm, PhaseCompleted)
 		return ctrl.Result{}, nil
 	}
 
@@ -501,11 +849,7 @@ func (r *DataMoverReconciler) cleanupClonedPVC(
 				dm.Status.RestoredPVCName,
 			)
 			metrics.RecordPVCCleanupOperation("already_deleted", dm.Namespace)
-			dm.Status.Phase = PhaseCompleted
-			if err := r.Status().Update(ctx, dm); err != nil {
-				metrics.RecordError("status_update_failed", PhaseCompleted, dm.Namespace)
-				return ctrl.Result{}, err
-			}
+			setPhase(dm, PhaseCompleted)
 			return ctrl.Result{}, nil
 		}
 		logger.Error(err, "Failed to get cloned PVC for cleanup")
@@ -519,11 +863,8 @@ func (r *DataMoverReconciler) cleanupClonedPVC(
 		logger.Error(err, "Failed to delete cloned PVC")
 		metrics.RecordError("pvc_delete_failed", PhaseCleaningUp, dm.Namespace)
 		metrics.RecordPVCCleanupOperation("failure", dm.Namespace)
-		dm.Status.Phase = PhaseFailed
-		if err := r.Status().Update(ctx, dm); err != nil {
-			metrics.RecordError("status_update_failed", PhaseFailed, dm.Namespace)
-			return ctrl.Result{}, err
-		}
+		r.failDataMover(dm, ReasonCleanupFailed,
+			fmt.Sprintf("Failed to delete cloned PVC %s: %v", pvc.Name, err))
 		return ctrl.Result{}, nil
 	}
 
@@ -533,17 +874,59 @@ func (r *DataMoverReconciler) cleanupClonedPVC(
 		dm.Status.RestoredPVCName,
 	)
 	metrics.RecordPVCCleanupOperation("success", dm.Namespace)
-	dm.Status.Phase = PhaseCompleted
-	if err := r.Status().Update(ctx, dm); err != nil {
-		metrics.RecordError("status_update_failed", PhaseCompleted, dm.Namespace)
-		return ctrl.Result{}, err
-	}
+	r.Recorder.Eventf(dm, corev1.EventTypeNormal, EventCloneDeleted,
+		"Deleted cloned PVC %s", dm.Status.RestoredPVCName)
+	setPhase(dm, PhaseCompleted)
 
 	return ctrl.Result{}, nil
 }
 
+// failDataMover moves the DataMover to the Failed phase and records the reason in its conditions.
+// The failure is counted once, on the transition, as failed DataMovers are reconciled until they expire,
+// and only once the status recording it was written.
+func (r *DataMoverReconciler) failDataMover(
+	dm *datamoverv1alpha1.DataMover,
+	reason, message string,
+) {
+	transition := dm.Status.Phase != PhaseFailed
+	r.afterStatusWrite(dm, func() {
+		if transition {
+			metrics.RecordOperationFailure(PhaseFailed, dm.Namespace)
+			metrics.RecordDataSyncOperation("failure", dm.Namespace)
+		}
+		r.Recorder.Event(dm, corev1.EventTypeWarning, reason, message)
+	})
+	meta.SetStatusCondition(&dm.Status.Conditions, metav1.Condition{
+		Type:               ConditionFailed,
+		Status:             metav1.ConditionTrue,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: dm.Generation,
+	})
+	setPhase(dm, PhaseFailed)
+}
+
+// isJobFinished reports whether a Job succeeded or stopped retrying after a failure
+func isJobFinished(job *batchv1.Job) bool {
+	return job.Status.Succeeded > 0 || isJobFailed(job)
+}
+
+// isJobFailed reports whether a Job stopped retrying after a failure
+func isJobFailed(job *batchv1.Job) bool {
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return job.Spec.BackoffLimit != nil && job.Status.Failed >= *job.Spec.BackoffLimit+1
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *DataMoverReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("datamover-controller")
+	}
+
 	// We also need to "own" the created objects so that Reconcile is triggered if they change
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&datamoverv1alpha1.DataMover{}).
@@ -19,23 +19,33 @@ package controller
 import (
 	"context"
 	"fmt"
-	"sort"
 	"time"
 
 	"github.com/go-logr/logr"
-	"github.com/robfig/cron/v3"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	datamoverv1alpha1 "a-cup-of.coffee/datamover-operator/api/v1alpha1"
 )
 
+const (
+	// ConditionScheduleValid reports whether the cron schedule, time zone and backup windows can be parsed
+	ConditionScheduleValid = "ScheduleValid"
+
+	ReasonValidSchedule   = "ValidSchedule"
+	ReasonInvalidSchedule = "InvalidSchedule"
+)
+
 // DataMoverScheduleReconciler reconciles a DataMoverSchedule object
 type DataMoverScheduleReconciler struct {
 	client.Client
@@ -48,6 +58,7 @@ type DataMoverScheduleReconciler struct {
 // +kubebuilder:rbac:groups=datamover.a-cup-of.coffee,resources=datamoverschedules/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=datamover.a-cup-of.coffee,resources=datamoverschedules/finalizers,verbs=update
 // +kubebuilder:rbac:groups=datamover.a-cup-of.coffee,resources=datamovers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=datamover.a-cup-of.coffee,resources=blackoutperiods,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -61,183 +72,269 @@ func (r *DataMoverScheduleReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
-	// Don't schedule anything if suspended
-	if dataMoverSchedule.Spec.Suspend {
-		logger.V(1).Info("DataMoverSchedule is suspended, skipping")
-		return ctrl.Result{}, nil
+	// The status is recomputed on every reconcile, and written once at the end
+	original := dataMoverSchedule.DeepCopy()
+	result, err := r.reconcileSchedule(ctx, &dataMoverSchedule)
+	if patchErr := r.patchStatus(ctx, original, &dataMoverSchedule); patchErr != nil {
+		return ctrl.Result{}, patchErr
 	}
+	return result, err
+}
 
-	// Parse the cron schedule
-	cronSchedule, err := cron.ParseStandard(dataMoverSchedule.Spec.Schedule)
-	if err != nil {
-		logger.Error(err, "unable to parse cron schedule", "schedule", dataMoverSchedule.Spec.Schedule)
-		r.Recorder.Eventf(&dataMoverSchedule, corev1.EventTypeWarning, "InvalidSchedule",
-			"Invalid cron schedule: %s", dataMoverSchedule.Spec.Schedule)
-		return ctrl.Result{}, err
-	}
+// reconcileSchedule prunes finished runs, refreshes the status and creates the run due now
+func (r *DataMoverScheduleReconciler) reconcileSchedule(
+	ctx context.Context,
+	dataMoverSchedule *datamoverv1alpha1.DataMoverSchedule,
+) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
 
 	// Get all DataMover jobs created by this DataMoverSchedule
 	var childDataMovers datamoverv1alpha1.DataMoverList
-	if err := r.List(ctx, &childDataMovers, client.InNamespace(req.Namespace),
-		client.MatchingLabels{"datamoverschedule": req.Name}); err != nil {
+	if err := r.List(ctx, &childDataMovers, client.InNamespace(dataMoverSchedule.Namespace),
+		client.MatchingLabels{LabelSchedule: dataMoverSchedule.Name}); err != nil {
 		logger.Error(err, "unable to list child DataMovers")
 		return ctrl.Result{}, err
 	}
-
-	// Separate active and finished jobs
-	var activeJobs []*datamoverv1alpha1.DataMover
-	var successfulJobs []*datamoverv1alpha1.DataMover
-	var failedJobs []*datamoverv1alpha1.DataMover
-
-	for i := range childDataMovers.Items {
-		dataMover := &childDataMovers.Items[i]
-		switch dataMover.Status.Phase {
-		case "Completed":
-			successfulJobs = append(successfulJobs, dataMover)
-		case "Failed":
-			failedJobs = append(failedJobs, dataMover)
-		default:
-			activeJobs = append(activeJobs, dataMover)
-		}
+	// The status is computed before pruning, so runs pruned right away are still accounted for
+	runs := groupRuns(childDataMovers.Items)
+	setRunStatus(dataMoverSchedule, runs)
+	// Retries are decided on all the runs, as the failed run to retry may be pruned
+	allRuns := runs
+	runs = r.pruneRuns(ctx, dataMoverSchedule, runs)
+	dataMoverSchedule.Status.SuccessfulJobs = int32(len(runs.successful))
+	dataMoverSchedule.Status.FailedJobs = int32(len(runs.failed))
+	if err := r.applyFailurePolicy(ctx, dataMoverSchedule); err != nil {
+		return ctrl.Result{}, err
 	}
+	setSuspendedCondition(dataMoverSchedule)
 
-	// Sort jobs by creation timestamp
-	sort.Slice(successfulJobs, func(i, j int) bool {
-		return successfulJobs[i].CreationTimestamp.Before(&successfulJobs[j].CreationTimestamp)
-	})
-	sort.Slice(failedJobs, func(i, j int) bool {
-		return failedJobs[i].CreationTimestamp.Before(&failedJobs[j].CreationTimestamp)
-	})
-
-	// Clean up old jobs based on history limits
-	successfulJobsHistoryLimit := int32(3)
-	if dataMoverSchedule.Spec.SuccessfulJobsHistoryLimit != nil {
-		successfulJobsHistoryLimit = *dataMoverSchedule.Spec.SuccessfulJobsHistoryLimit
+	// Manual runs start even while the schedule is suspended or its cron schedule is invalid
+	if token := pendingTrigger(dataMoverSchedule); token != "" {
+		if err := r.triggerRun(ctx, dataMoverSchedule, runs.active, token); err != nil {
+			return ctrl.Result{}, err
+		}
+		// Evaluate the cron schedule once the manual run is listed as active
+		return ctrl.Result{Requeue: true}, nil
 	}
 
-	failedJobsHistoryLimit := int32(1)
-	if dataMoverSchedule.Spec.FailedJobsHistoryLimit != nil {
-		failedJobsHistoryLimit = *dataMoverSchedule.Spec.FailedJobsHistoryLimit
+	// Don't schedule anything if suspended
+	if dataMoverSchedule.Spec.Suspend {
+		logger.V(1).Info("DataMoverSchedule is suspended, skipping")
+		dataMoverSchedule.Status.NextScheduleTime = nil
+		return ctrl.Result{}, nil
 	}
 
-	// Delete old successful jobs
-	if int32(len(successfulJobs)) > successfulJobsHistoryLimit {
-		for i := 0; i < len(successfulJobs)-int(successfulJobsHistoryLimit); i++ {
-			if err := r.Delete(ctx, successfulJobs[i], client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil {
-				logger.Error(err, "unable to delete old successful DataMover", "datamover", successfulJobs[i].Name)
-			} else {
-				logger.V(1).Info("deleted old successful DataMover", "datamover", successfulJobs[i].Name)
-			}
-		}
+	// Parse the cron schedule in its time zone
+	cronSchedule, err := parseSchedule(dataMoverSchedule.Spec.Schedule, dataMoverSchedule.Spec.TimeZone,
+		hashSeed(dataMoverSchedule))
+	if err != nil {
+		logger.Error(err, "unable to parse cron schedule", "schedule", dataMoverSchedule.Spec.Schedule)
+		r.invalidSchedule(dataMoverSchedule,
+			fmt.Sprintf("Invalid cron schedule %q: %v", dataMoverSchedule.Spec.Schedule, err))
+		return ctrl.Result{}, nil
 	}
-
-	// Delete old failed jobs
-	if int32(len(failedJobs)) > failedJobsHistoryLimit {
-		for i := 0; i < len(failedJobs)-int(failedJobsHistoryLimit); i++ {
-			if err := r.Delete(ctx, failedJobs[i], client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil {
-				logger.Error(err, "unable to delete old failed DataMover", "datamover", failedJobs[i].Name)
-			} else {
-				logger.V(1).Info("deleted old failed DataMover", "datamover", failedJobs[i].Name)
-			}
-		}
+	windows, err := parseWindows(dataMoverSchedule)
+	if err != nil {
+		logger.Error(err, "unable to parse backup windows")
+		r.invalidSchedule(dataMoverSchedule, fmt.Sprintf("Invalid backup window: %v", err))
+		return ctrl.Result{}, nil
 	}
+	setScheduleValid(dataMoverSchedule, metav1.ConditionTrue, ReasonValidSchedule, "The cron schedule is valid")
 
-	// Calculate next scheduled time
+	// Calculate next scheduled time. Runs start once their jitter offset elapsed, and keep
+	// the time of the cron expression as scheduled time.
 	now := time.Now()
-	nextTime := cronSchedule.Next(now)
-
-	// Check if we should create a new job
-	var lastScheduleTime *metav1.Time
-	if dataMoverSchedule.Status.LastScheduleTime != nil {
-		lastScheduleTime = dataMoverSchedule.Status.LastScheduleTime
+	offset := jitterOffset(dataMoverSchedule)
+	nextTime := cronSchedule.Next(now.Add(-offset))
+	if !nextTime.IsZero() {
+		nextTime = nextTime.Add(offset)
 	}
+	dataMoverSchedule.Status.NextScheduleTime = &metav1.Time{Time: nextTime}
+	requeue := ctrl.Result{RequeueAfter: nextTime.Sub(now)}
 
-	scheduledTime := cronSchedule.Next(now.Add(-time.Second))
-	if scheduledTime.After(now) {
-		// Next schedule is in the future, wait
-		logger.V(1).Info("next schedule is in the future", "scheduledTime", scheduledTime)
-		return ctrl.Result{RequeueAfter: nextTime.Sub(now)}, nil
+	// Runs only start within the backup windows and outside blackout periods
+	blackouts, err := r.blackoutsFor(ctx, dataMoverSchedule)
+	if err != nil {
+		return ctrl.Result{}, err
 	}
-
-	// Check if we already created a job for this schedule
-	if lastScheduleTime != nil && scheduledTime.Before(lastScheduleTime.Add(time.Minute)) {
-		// We already created a job for this minute
-		logger.V(1).Info("job already created for this schedule", "scheduledTime", scheduledTime)
-		return ctrl.Result{RequeueAfter: nextTime.Sub(now)}, nil
+	closed := closedUntil(windows, blackouts, now)
+	setInWindowCondition(dataMoverSchedule, closed)
+
+	// Find the run to start, catching up on runs missed while the operator was unavailable
+	scheduledTime := r.dueRun(ctx, dataMoverSchedule, cronSchedule, now.Add(-offset))
+	if scheduledTime.IsZero() {
+		logger.V(1).Info("no run to start, waiting for the next schedule", "nextTime", nextTime)
+		// A failed run may be retried until the next scheduled run
+		return r.retryFailedRun(ctx, dataMoverSchedule, allRuns, closed, nextTime, now)
+	}
+	if closed != nil {
+		return r.holdRun(ctx, dataMoverSchedule, closed, scheduledTime, nextTime, now), nil
 	}
 
 	// Create new DataMover job
 	dataMoverName := fmt.Sprintf("%s-%d", dataMoverSchedule.Name, scheduledTime.Unix())
-	dataMover := &datamoverv1alpha1.DataMover{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      dataMoverName,
-			Namespace: dataMoverSchedule.Namespace,
-			Labels: map[string]string{
-				"datamoverschedule":          dataMoverSchedule.Name,
-				"datamoverschedule-schedule": fmt.Sprintf("%d", scheduledTime.Unix()),
-			},
-		},
-		Spec: datamoverv1alpha1.DataMoverSpec{
-			SourcePVC:            dataMoverSchedule.Spec.SourcePvc,
-			SecretName:           dataMoverSchedule.Spec.SecretName,
-			AddTimestampPrefix:   dataMoverSchedule.Spec.AddTimestampPrefix,
-			DeletePvcAfterBackup: dataMoverSchedule.Spec.DeletePvcAfterBackup,
-			AdditionalEnv:        dataMoverSchedule.Spec.AdditionalEnv,
-			Image:                dataMoverSchedule.Spec.Image,
-		},
+	dataMover := newScheduledDataMover(dataMoverSchedule, dataMoverName, scheduledTime)
+	if _, err := r.startRun(ctx, dataMoverSchedule, runs.active, dataMover, scheduledTime); err != nil {
+		return ctrl.Result{}, err
+	}
+	// The run is handled, even when the concurrency policy skipped it
+	dataMoverSchedule.Status.LastScheduleTime = &metav1.Time{Time: scheduledTime}
+	dataMoverSchedule.Status.DeferredScheduleTime = nil
+
+	// Requeue for next schedule
+	return requeue, nil
+}
+
+// startRun applies the concurrency policy, then creates the DataMover of a run.
+// It reports false when the run was skipped because previous runs are still active.
+func (r *DataMoverScheduleReconciler) startRun(
+	ctx context.Context,
+	dataMoverSchedule *datamoverv1alpha1.DataMoverSchedule,
+	active []*datamoverv1alpha1.DataMover,
+	dataMover *datamoverv1alpha1.DataMover,
+	scheduledTime time.Time,
+) (bool, error) {
+	logger := log.FromContext(ctx)
+	dataMoverName := dataMover.Name
+
+	// Apply the concurrency policy while previous runs are still active
+	if len(active) > 0 {
+		switch concurrencyPolicy(dataMoverSchedule) {
+		case datamoverv1alpha1.ForbidConcurrent:
+			logger.Info("skipping run, a previous run is still active",
+				"datamover", dataMoverName, "active", len(active))
+			r.Recorder.Eventf(dataMoverSchedule, corev1.EventTypeNormal, EventJobSkipped,
+				"Skipped DataMover job %s, %d previous job(s) still active", dataMoverName, len(active))
+			skipRuns(dataMoverSchedule, 1, scheduledTime, skipReasonForbidden)
+			return false, nil
+		case datamoverv1alpha1.ReplaceConcurrent:
+			if err := r.replaceActiveRuns(ctx, dataMoverSchedule, active, dataMoverName); err != nil {
+				return false, err
+			}
+		}
 	}
 
 	// Set DataMoverSchedule as owner of the DataMover
-	if err := controllerutil.SetControllerReference(&dataMoverSchedule, dataMover, r.Scheme); err != nil {
+	if err := controllerutil.SetControllerReference(dataMoverSchedule, dataMover, r.Scheme); err != nil {
 		logger.Error(err, "unable to set controller reference")
-		return ctrl.Result{}, err
+		return false, err
 	}
 
-	if err := r.Create(ctx, dataMover); err != nil {
+	if err := r.Create(ctx, dataMover); errors.IsAlreadyExists(err) {
+		// Created by a previous reconcile which failed to record it in the status
+		logger.Info("DataMover job already exists", "datamover", dataMoverName)
+		if err := r.Get(ctx, client.ObjectKeyFromObject(dataMover), dataMover); err != nil {
+			return false, err
+		}
+	} else if err != nil {
 		logger.Error(err, "unable to create DataMover job", "datamover", dataMoverName)
-		r.Recorder.Eventf(&dataMoverSchedule, corev1.EventTypeWarning, "JobCreationFailed",
+		r.Recorder.Eventf(dataMoverSchedule, corev1.EventTypeWarning, "JobCreationFailed",
 			"Failed to create DataMover job: %s", dataMoverName)
-		return ctrl.Result{}, err
+		return false, err
+	} else {
+		logger.Info("created DataMover job", "datamover", dataMoverName, "scheduledTime", scheduledTime)
+		r.Recorder.Eventf(dataMoverSchedule, corev1.EventTypeNormal, "JobCreated",
+			"Created DataMover job: %s", dataMoverName)
 	}
 
-	logger.Info("created DataMover job", "datamover", dataMoverName, "scheduledTime", scheduledTime)
-	r.Recorder.Eventf(&dataMoverSchedule, corev1.EventTypeNormal, "JobCreated",
-		"Created DataMover job: %s", dataMoverName)
+	// Record the new run, the next reconcile lists it with the others
+	dataMoverSchedule.Status.Active = append(dataMoverSchedule.Status.Active, runReference(dataMover))
+	dataMoverSchedule.Status.ActiveJobs = int32(len(dataMoverSchedule.Status.Active))
+	dataMoverSchedule.Status.LastRunPhase = lastRunPhasePending
+	return true, nil
+}
 
-	// Update status
-	now = time.Now()
-	dataMoverSchedule.Status.LastScheduleTime = &metav1.Time{Time: scheduledTime}
+// pruneRuns deletes the finished runs beyond the history limits and returns the remaining runs
+func (r *DataMoverScheduleReconciler) pruneRuns(
+	ctx context.Context,
+	dataMoverSchedule *datamoverv1alpha1.DataMoverSchedule,
+	runs scheduleRuns,
+) scheduleRuns {
+	// Clean up old jobs based on history limits
+	successfulJobsHistoryLimit := int32(3)
+	if dataMoverSchedule.Spec.SuccessfulJobsHistoryLimit != nil {
+		successfulJobsHistoryLimit = *dataMoverSchedule.Spec.SuccessfulJobsHistoryLimit
+	}
 
-	// Update active jobs list
-	activeRefs := make([]corev1.ObjectReference, 0, len(activeJobs)+1)
-	for _, job := range activeJobs {
-		activeRefs = append(activeRefs, corev1.ObjectReference{
-			Kind:      "DataMover",
-			Namespace: job.Namespace,
-			Name:      job.Name,
-			UID:       job.UID,
-		})
-	}
-	// Add the new job to active list
-	activeRefs = append(activeRefs, corev1.ObjectReference{
-		Kind:      "DataMover",
-		Namespace: dataMover.Namespace,
-		Name:      dataMover.Name,
-		UID:       dataMover.UID,
-	})
+	failedJobsHistoryLimit := int32(1)
+	if dataMoverSchedule.Spec.FailedJobsHistoryLimit != nil {
+		failedJobsHistoryLimit = *dataMoverSchedule.Spec.FailedJobsHistoryLimit
+	}
 
-	dataMoverSchedule.Status.Active = activeRefs
-	dataMoverSchedule.Status.ActiveJobs = int32(len(activeRefs))
-	dataMoverSchedule.Status.SuccessfulJobs = int32(len(successfulJobs))
-	dataMoverSchedule.Status.FailedJobs = int32(len(failedJobs))
+	runs.successful = r.deleteOldRuns(ctx, runs.successful, int(successfulJobsHistoryLimit), "successful")
+	runs.failed = r.deleteOldRuns(ctx, runs.failed, int(failedJobsHistoryLimit), "failed")
+	// Cancelled runs are kept as long as failed ones
+	runs.cancelled = r.deleteOldRuns(ctx, runs.cancelled, int(failedJobsHistoryLimit), "cancelled")
+	return runs
+}
 
-	if err := r.Status().Update(ctx, &dataMoverSchedule); err != nil {
-		logger.Error(err, "unable to update DataMoverSchedule status")
-		return ctrl.Result{}, err
+// deleteOldRuns deletes the oldest runs beyond the limit and returns the runs that remain.
+// Runs that could not be deleted are kept, and deleted on a later reconcile.
+func (r *DataMoverScheduleReconciler) deleteOldRuns(
+	ctx context.Context,
+	runs []*datamoverv1alpha1.DataMover,
+	limit int,
+	kind string,
+) []*datamoverv1alpha1.DataMover {
+	logger := log.FromContext(ctx)
+
+	var kept []*datamoverv1alpha1.DataMover
+	for i, dm := range runs {
+		if i >= len(runs)-limit {
+			kept = append(kept, dm)
+			continue
+		}
+		if err := r.Delete(ctx, dm, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil &&
+			!errors.IsNotFound(err) {
+			logger.Error(err, "unable to delete old "+kind+" DataMover", "datamover", dm.Name)
+			kept = append(kept, dm)
+		} else {
+			logger.V(1).Info("deleted old "+kind+" DataMover", "datamover", dm.Name)
+		}
 	}
+	return kept
+}
 
-	// Requeue for next schedule
-	return ctrl.Result{RequeueAfter: nextTime.Sub(now)}, nil
+// invalidSchedule reports a schedule that cannot be evaluated, with an event when it becomes invalid.
+// Retrying does not help, the schedule is reconciled again once its spec changes.
+func (r *DataMoverScheduleReconciler) invalidSchedule(schedule *datamoverv1alpha1.DataMoverSchedule, message string) {
+	if setScheduleValid(schedule, metav1.ConditionFalse, ReasonInvalidSchedule, message) {
+		r.Recorder.Event(schedule, corev1.EventTypeWarning, ReasonInvalidSchedule, message)
+	}
+	schedule.Status.NextScheduleTime = nil
+}
+
+// setScheduleValid records whether the schedule is valid, reporting whether the condition changed
+func setScheduleValid(
+	schedule *datamoverv1alpha1.DataMoverSchedule,
+	status metav1.ConditionStatus,
+	reason, message string,
+) bool {
+	return meta.SetStatusCondition(&schedule.Status.Conditions, metav1.Condition{
+		Type:               ConditionScheduleValid,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: schedule.Generation,
+	})
+}
+
+// patchStatus writes the status computed during a reconcile, if it changed
+func (r *DataMoverScheduleReconciler) patchStatus(
+	ctx context.Context,
+	original, schedule *datamoverv1alpha1.DataMoverSchedule,
+) error {
+	if equality.Semantic.DeepEqual(original.Status, schedule.Status) {
+		return nil
+	}
+	if err := r.Status().Patch(ctx, schedule, client.MergeFrom(original)); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		log.FromContext(ctx).Error(err, "unable to update DataMoverSchedule status")
+		return err
+	}
+	return nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -248,6 +345,9 @@ func (r *DataMoverScheduleReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&datamoverv1alpha1.DataMoverSchedule{}).
+		// Runs changing phase refresh the status of their schedule
 		Owns(&datamoverv1alpha1.DataMover{}).
+		// Blackout periods being created, changed or deleted move the start of deferred runs
+		Watches(&datamoverv1alpha1.BlackoutPeriod{}, handler.EnqueueRequestsFromMapFunc(r.schedulesForBlackout)).
 		Complete(r)
 }
@@ -0,0 +1,307 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	datamoverv1alpha1 "a-cup-of.coffee/datamover-operator/api/v1alpha1"
+)
+
+const (
+	// AnnotationAutoSuspended records that a schedule was suspended by its failure policy
+	AnnotationAutoSuspended = "datamover.a-cup-of.coffee/auto-suspended"
+	// LabelAttempt holds the attempt number of the retries of a scheduled run, from 2 on
+	LabelAttempt = "datamoverschedule-attempt"
+
+	// ReasonTooManyFailures is the reason of the Suspended condition of a schedule suspended by its failure policy
+	ReasonTooManyFailures = "TooManyFailures"
+
+	// EventAutoSuspended is emitted when a schedule is suspended after consecutive failed runs
+	EventAutoSuspended = "AutoSuspended"
+	// EventRetrying is emitted when a failed run is retried
+	EventRetrying = "Retrying"
+
+	// defaultRetryDelay is the delay before retrying a failed run when the failure policy sets none
+	defaultRetryDelay = 5 * time.Minute
+)
+
+// retryDelay returns how long to wait after a failed run before retrying it
+func retryDelay(policy *datamoverv1alpha1.ScheduleFailurePolicy) time.Duration {
+	if policy.RetryDelay == nil {
+		return defaultRetryDelay
+	}
+	return policy.RetryDelay.Duration
+}
+
+// isAutoSuspended reports whether a schedule was suspended by its failure policy
+func isAutoSuspended(schedule *datamoverv1alpha1.DataMoverSchedule) bool {
+	_, found := schedule.Annotations[AnnotationAutoSuspended]
+	return found
+}
+
+// countFailures updates the number of consecutive failed runs with the runs that finished since
+// the previous reconcile, which are the ones finished after the last known success and failure.
+// A scheduled run only counts as failed once its last attempt failed: attempts retried later are
+// never counted, and a failure awaiting its retry is counted once it is no longer awaited, as the
+// last failure time does not move past it until then.
+// It must run before the last success and failure times are updated.
+func countFailures(schedule *datamoverv1alpha1.DataMoverSchedule, runs scheduleRuns) {
+	status := &schedule.Status
+
+	type outcome struct {
+		finished time.Time
+		failed   bool
+	}
+	var outcomes []outcome
+	for _, dm := range runs.successful {
+		if finished := finishedAt(dm); status.LastSuccessfulTime == nil || finished.After(status.LastSuccessfulTime.Time) {
+			outcomes = append(outcomes, outcome{finished: finished})
+		}
+	}
+	for _, dm := range runs.failed {
+		if finished := finishedAt(dm); status.LastFailureTime == nil || finished.After(status.LastFailureTime.Time) {
+			if retriedLater(dm, runs) || awaitingRetry(schedule, dm, runs) {
+				continue
+			}
+			outcomes = append(outcomes, outcome{finished: finished, failed: true})
+		}
+	}
+	sort.SliceStable(outcomes, func(i, j int) bool { return outcomes[i].finished.Before(outcomes[j].finished) })
+
+	for _, o := range outcomes {
+		if o.failed {
+			status.ConsecutiveFailures++
+		} else {
+			status.ConsecutiveFailures = 0
+		}
+	}
+}
+
+// retriedLater reports whether a later attempt of the schedule slot of a run was started
+func retriedLater(dm *datamoverv1alpha1.DataMover, runs scheduleRuns) bool {
+	if dm.Labels[LabelTrigger] != "" {
+		return false
+	}
+	for _, group := range [][]*datamoverv1alpha1.DataMover{runs.active, runs.successful, runs.failed, runs.cancelled} {
+		for _, other := range group {
+			if other.Labels[LabelTrigger] == "" && runAttempt(other) > runAttempt(dm) &&
+				runScheduledTime(other).Equal(runScheduledTime(dm)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// awaitingRetry reports whether a failed run may still be retried before the next scheduled run.
+// Whether the retry actually starts, past closed windows, active runs or downtime, is only known
+// once a later attempt exists or the schedule moved to its next slot.
+func awaitingRetry(
+	schedule *datamoverv1alpha1.DataMoverSchedule,
+	dm *datamoverv1alpha1.DataMover,
+	runs scheduleRuns,
+) bool {
+	if retriedLater(dm, runs) {
+		return false
+	}
+	var nextTime time.Time
+	if schedule.Status.NextScheduleTime != nil {
+		nextTime = schedule.Status.NextScheduleTime.Time
+	}
+	attempt, _ := pendingRetry(schedule, dm, nextTime)
+	return attempt > 0
+}
+
+// settledFailures returns the failed runs that are not awaiting a retry
+func settledFailures(schedule *datamoverv1alpha1.DataMoverSchedule, runs scheduleRuns) []*datamoverv1alpha1.DataMover {
+	var settled []*datamoverv1alpha1.DataMover
+	for _, dm := range runs.failed {
+		if !awaitingRetry(schedule, dm, runs) {
+			settled = append(settled, dm)
+		}
+	}
+	return settled
+}
+
+// runAttempt returns the attempt number of a run, 1 for the run started by the schedule
+func runAttempt(dm *datamoverv1alpha1.DataMover) int {
+	attempt, err := strconv.Atoi(dm.Labels[LabelAttempt])
+	if err != nil || attempt < 1 {
+		return 1
+	}
+	return attempt
+}
+
+// runScheduledTime returns the time a run was scheduled at, from its label
+func runScheduledTime(dm *datamoverv1alpha1.DataMover) time.Time {
+	seconds, err := strconv.ParseInt(dm.Labels[LabelScheduledTime], 10, 64)
+	if err != nil {
+		return dm.CreationTimestamp.Time
+	}
+	return time.Unix(seconds, 0)
+}
+
+// pendingRetry returns the attempt number and time of the retry of the last run, when it failed
+// and the failure policy allows another attempt before the next scheduled run.
+// Only the run of the last schedule slot is retried, not one whose next run was skipped or deferred.
+// It returns a zero attempt when there is nothing to retry.
+func pendingRetry(
+	schedule *datamoverv1alpha1.DataMoverSchedule,
+	last *datamoverv1alpha1.DataMover,
+	nextTime time.Time,
+) (int, time.Time) {
+	policy := schedule.Spec.FailurePolicy
+	if policy == nil || policy.MaxRetries == 0 || last == nil || last.Status.Phase != PhaseFailed {
+		return 0, time.Time{}
+	}
+	// Manual runs are not retried
+	if last.Labels[LabelTrigger] != "" {
+		return 0, time.Time{}
+	}
+	lastSchedule := schedule.Status.LastScheduleTime
+	if lastSchedule == nil || !runScheduledTime(last).Equal(lastSchedule.Time) {
+		return 0, time.Time{}
+	}
+	attempt := runAttempt(last)
+	if attempt > int(policy.MaxRetries) {
+		return 0, time.Time{}
+	}
+	retryAt := finishedAt(last).Add(retryDelay(policy))
+	if !nextTime.IsZero() && !retryAt.Before(nextTime) {
+		return 0, time.Time{}
+	}
+	return attempt + 1, retryAt
+}
+
+// newRetryDataMover builds the DataMover retrying a failed run, for the same scheduled time
+func newRetryDataMover(
+	schedule *datamoverv1alpha1.DataMoverSchedule,
+	failed *datamoverv1alpha1.DataMover,
+	attempt int,
+) *datamoverv1alpha1.DataMover {
+	scheduledTime := runScheduledTime(failed)
+	name := fmt.Sprintf("%s-%d-retry-%d", schedule.Name, scheduledTime.Unix(), attempt-1)
+	dataMover := newScheduledDataMover(schedule, name, scheduledTime)
+	dataMover.Labels[LabelAttempt] = strconv.Itoa(attempt)
+	return dataMover
+}
+
+// retryFailedRun retries the last run when it failed, once the retry delay elapsed and while
+// runs may start. The result requeues for the retry, or keeps waiting for the next scheduled run.
+func (r *DataMoverScheduleReconciler) retryFailedRun(
+	ctx context.Context,
+	schedule *datamoverv1alpha1.DataMoverSchedule,
+	runs scheduleRuns,
+	closed *closedPeriod,
+	nextTime, now time.Time,
+) (ctrl.Result, error) {
+	requeue := ctrl.Result{RequeueAfter: nextTime.Sub(now)}
+	// Retries wait for the active runs, which may succeed
+	if len(runs.active) > 0 {
+		return requeue, nil
+	}
+	last := latest(runs.successful, runs.failed, runs.cancelled)
+	attempt, retryAt := pendingRetry(schedule, last, nextTime)
+	if attempt == 0 {
+		return requeue, nil
+	}
+	if closed != nil {
+		if closed.until.IsZero() {
+			return requeue, nil
+		}
+		if closed.until.After(retryAt) {
+			retryAt = closed.until
+		}
+		if !nextTime.IsZero() && !retryAt.Before(nextTime) {
+			return requeue, nil
+		}
+	}
+	if retryAt.After(now) {
+		return ctrl.Result{RequeueAfter: retryAt.Sub(now)}, nil
+	}
+
+	logger := log.FromContext(ctx)
+	logger.Info("retrying failed run", "datamover", last.Name, "attempt", attempt)
+	r.Recorder.Eventf(schedule, corev1.EventTypeNormal, EventRetrying,
+		"Retrying DataMover job %s, attempt %d of %d", last.Name, attempt, schedule.Spec.FailurePolicy.MaxRetries+1)
+	retry := newRetryDataMover(schedule, last, attempt)
+	if _, err := r.startRun(ctx, schedule, nil, retry, runScheduledTime(last)); err != nil {
+		return ctrl.Result{}, err
+	}
+	return requeue, nil
+}
+
+// applyFailurePolicy suspends a schedule after too many consecutive failed runs, and resets the
+// failure count once a suspended schedule is resumed. The suspension is recorded in spec.suspend,
+// so resuming works as for any suspended schedule.
+func (r *DataMoverScheduleReconciler) applyFailurePolicy(
+	ctx context.Context,
+	schedule *datamoverv1alpha1.DataMoverSchedule,
+) error {
+	logger := log.FromContext(ctx)
+
+	if isAutoSuspended(schedule) && !schedule.Spec.Suspend {
+		logger.Info("schedule resumed after being suspended by its failure policy")
+		patched := schedule.DeepCopy()
+		delete(patched.Annotations, AnnotationAutoSuspended)
+		if err := r.patchSchedule(ctx, schedule, patched); err != nil {
+			return err
+		}
+		schedule.Status.ConsecutiveFailures = 0
+		return nil
+	}
+
+	policy := schedule.Spec.FailurePolicy
+	if schedule.Spec.Suspend || policy == nil || policy.SuspendAfterFailures == nil ||
+		schedule.Status.ConsecutiveFailures < *policy.SuspendAfterFailures {
+		return nil
+	}
+
+	logger.Info("suspending schedule after consecutive failed runs", "failures", schedule.Status.ConsecutiveFailures)
+	patched := schedule.DeepCopy()
+	patched.Spec.Suspend = true
+	if patched.Annotations == nil {
+		patched.Annotations = map[string]string{}
+	}
+	patched.Annotations[AnnotationAutoSuspended] = time.Now().UTC().Format(time.RFC3339)
+	if err := r.patchSchedule(ctx, schedule, patched); err != nil {
+		return err
+	}
+	r.Recorder.Eventf(schedule, corev1.EventTypeWarning, EventAutoSuspended,
+		"Suspended after %d consecutive failed runs, set spec.suspend to false to resume",
+		schedule.Status.ConsecutiveFailures)
+	return nil
+}
+
+// patchSchedule patches the metadata and spec of a schedule, then copies them to the schedule.
+// The patch is applied to a copy, as the response would replace the status computed so far.
+func (r *DataMoverScheduleReconciler) patchSchedule(
+	ctx context.Context,
+	schedule, patched *datamoverv1alpha1.DataMoverSchedule,
+) error {
+	if err := r.Patch(ctx, patched, client.MergeFrom(schedule)); err != nil {
+		log.FromContext(ctx).Error(err, "unable to patch DataMoverSchedule")
+		return err
+	}
+	schedule.ObjectMeta = patched.ObjectMeta
+	schedule.Spec = patched.Spec
+	return nil
+}
+
+// suspendedReason returns the reason and message of the Suspended condition of a suspended schedule
+func suspendedReason(schedule *datamoverv1alpha1.DataMoverSchedule) (reason, message string) {
+	if isAutoSuspended(schedule) {
+		return ReasonTooManyFailures, fmt.Sprintf(
+			"Suspended by the failure policy after %d consecutive failed runs, set spec.suspend to false to resume",
+			schedule.Status.ConsecutiveFailures)
+	}
+	return ReasonSuspended, "No run is scheduled while spec.suspend is set"
+}
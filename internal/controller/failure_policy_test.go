@@ -0,0 +1,308 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	datamoverv1alpha1 "a-cup-of.coffee/datamover-operator/api/v1alpha1"
+)
+
+var _ = Describe("Failure policy", func() {
+	scheduled := time.Date(2025, 6, 3, 2, 0, 0, 0, time.UTC)
+	nextTime := scheduled.Add(24 * time.Hour)
+
+	var schedule *datamoverv1alpha1.DataMoverSchedule
+
+	// run returns a run of the schedule slot, which finished after the given delay as runs without
+	// phase timings finish at their creation time
+	run := func(name string, finished time.Duration, phase string) *datamoverv1alpha1.DataMover {
+		return &datamoverv1alpha1.DataMover{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				CreationTimestamp: metav1.NewTime(scheduled.Add(finished)),
+				Labels: map[string]string{
+					LabelSchedule:      "nightly",
+					LabelScheduledTime: fmt.Sprintf("%d", scheduled.Unix()),
+				},
+			},
+			Status: datamoverv1alpha1.DataMoverStatus{Phase: phase},
+		}
+	}
+
+	BeforeEach(func() {
+		schedule = &datamoverv1alpha1.DataMoverSchedule{
+			ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "apps"},
+			Spec: datamoverv1alpha1.DataMoverScheduleSpec{
+				FailurePolicy: &datamoverv1alpha1.ScheduleFailurePolicy{
+					MaxRetries: 2,
+					RetryDelay: &metav1.Duration{Duration: 10 * time.Minute},
+				},
+			},
+			Status: datamoverv1alpha1.DataMoverScheduleStatus{
+				LastScheduleTime: &metav1.Time{Time: scheduled},
+			},
+		}
+	})
+
+	Context("consecutive failures", func() {
+		// slot returns a run of an earlier schedule slot, which is no longer retried
+		slot := func(name string, finished time.Duration, phase string) *datamoverv1alpha1.DataMover {
+			dm := run(name, finished, phase)
+			dm.Labels[LabelScheduledTime] = fmt.Sprintf("%d", scheduled.Add(finished-24*time.Hour).Unix())
+			return dm
+		}
+
+		It("should count the failures finished since the last success", func() {
+			status := &schedule.Status
+			countFailures(schedule, scheduleRuns{
+				successful: []*datamoverv1alpha1.DataMover{slot("first", time.Minute, PhaseCompleted)},
+				failed: []*datamoverv1alpha1.DataMover{
+					slot("second", 2*time.Minute, PhaseFailed),
+					slot("third", 3*time.Minute, PhaseFailed),
+				},
+			})
+			Expect(status.ConsecutiveFailures).To(Equal(int32(2)))
+		})
+
+		It("should reset the count when a run succeeds", func() {
+			status := &schedule.Status
+			status.ConsecutiveFailures = 4
+			countFailures(schedule, scheduleRuns{
+				successful: []*datamoverv1alpha1.DataMover{slot("second", 2*time.Minute, PhaseCompleted)},
+				failed:     []*datamoverv1alpha1.DataMover{slot("first", time.Minute, PhaseFailed)},
+			})
+			Expect(status.ConsecutiveFailures).To(BeZero())
+		})
+
+		It("should not count the same failure twice", func() {
+			status := &schedule.Status
+			runs := scheduleRuns{failed: []*datamoverv1alpha1.DataMover{slot("first", time.Minute, PhaseFailed)}}
+
+			setRunStatus(schedule, runs)
+			setRunStatus(schedule, runs)
+			Expect(status.ConsecutiveFailures).To(Equal(int32(1)))
+		})
+
+		It("should count a scheduled run once its last attempt failed", func() {
+			status := &schedule.Status
+			status.NextScheduleTime = &metav1.Time{Time: nextTime}
+			first := run("nightly-1", 5*time.Minute, PhaseFailed)
+			second := run("nightly-1-retry-1", 20*time.Minute, PhaseFailed)
+			second.Labels[LabelAttempt] = "2"
+			third := run("nightly-1-retry-2", 35*time.Minute, PhaseFailed)
+			third.Labels[LabelAttempt] = "3"
+
+			setRunStatus(schedule, scheduleRuns{failed: []*datamoverv1alpha1.DataMover{first}})
+			Expect(status.ConsecutiveFailures).To(BeZero())
+			setRunStatus(schedule, scheduleRuns{failed: []*datamoverv1alpha1.DataMover{first, second}})
+			Expect(status.ConsecutiveFailures).To(BeZero())
+			setRunStatus(schedule, scheduleRuns{failed: []*datamoverv1alpha1.DataMover{first, second, third}})
+			Expect(status.ConsecutiveFailures).To(Equal(int32(1)))
+		})
+
+		It("should count a failure that cannot be retried before the next run", func() {
+			schedule.Status.NextScheduleTime = &metav1.Time{Time: scheduled.Add(10 * time.Minute)}
+			countFailures(schedule, scheduleRuns{
+				failed: []*datamoverv1alpha1.DataMover{run("nightly-1", 5*time.Minute, PhaseFailed)},
+			})
+			Expect(schedule.Status.ConsecutiveFailures).To(Equal(int32(1)))
+		})
+
+		It("should count a failure whose retry was held back by a closed window", func() {
+			status := &schedule.Status
+			status.NextScheduleTime = &metav1.Time{Time: nextTime}
+			failed := run("nightly-1", 5*time.Minute, PhaseFailed)
+
+			setRunStatus(schedule, scheduleRuns{failed: []*datamoverv1alpha1.DataMover{failed}})
+			Expect(status.ConsecutiveFailures).To(BeZero())
+			Expect(status.LastFailureTime).To(BeNil())
+
+			// The backup window closed before the retry, the schedule moved to its next run
+			next := run("nightly-2", 24*time.Hour, PhaseCreatingPod)
+			next.Labels[LabelScheduledTime] = fmt.Sprintf("%d", nextTime.Unix())
+			status.LastScheduleTime = &metav1.Time{Time: nextTime}
+			status.NextScheduleTime = &metav1.Time{Time: nextTime.Add(24 * time.Hour)}
+			runs := scheduleRuns{
+				active: []*datamoverv1alpha1.DataMover{next},
+				failed: []*datamoverv1alpha1.DataMover{failed},
+			}
+			setRunStatus(schedule, runs)
+			Expect(status.ConsecutiveFailures).To(Equal(int32(1)))
+			Expect(status.LastFailureTime.Time).To(Equal(scheduled.Add(5 * time.Minute)))
+
+			setRunStatus(schedule, runs)
+			Expect(status.ConsecutiveFailures).To(Equal(int32(1)))
+		})
+
+		It("should reset the count when a retry succeeds", func() {
+			status := &schedule.Status
+			status.ConsecutiveFailures = 2
+			retry := run("nightly-1-retry-1", 20*time.Minute, PhaseCompleted)
+			retry.Labels[LabelAttempt] = "2"
+			countFailures(schedule, scheduleRuns{
+				successful: []*datamoverv1alpha1.DataMover{retry},
+				failed:     []*datamoverv1alpha1.DataMover{run("nightly-1", 5*time.Minute, PhaseFailed)},
+			})
+			Expect(status.ConsecutiveFailures).To(BeZero())
+		})
+	})
+
+	Context("retries", func() {
+		It("should retry a failed run after the retry delay", func() {
+			attempt, retryAt := pendingRetry(schedule, run("nightly-1", 5*time.Minute, PhaseFailed), nextTime)
+			Expect(attempt).To(Equal(2))
+			Expect(retryAt).To(Equal(scheduled.Add(15 * time.Minute)))
+		})
+
+		It("should stop after the last attempt", func() {
+			failed := run("nightly-1-retry-2", 5*time.Minute, PhaseFailed)
+			failed.Labels[LabelAttempt] = "3"
+			attempt, _ := pendingRetry(schedule, failed, nextTime)
+			Expect(attempt).To(BeZero())
+		})
+
+		It("should only retry within the schedule slot", func() {
+			attempt, _ := pendingRetry(schedule, run("nightly-1", 5*time.Minute, PhaseFailed), scheduled.Add(10*time.Minute))
+			Expect(attempt).To(BeZero())
+
+			schedule.Status.LastScheduleTime = &metav1.Time{Time: nextTime}
+			attempt, _ = pendingRetry(schedule, run("nightly-1", 5*time.Minute, PhaseFailed), nextTime.Add(time.Hour))
+			Expect(attempt).To(BeZero())
+		})
+
+		It("should not retry successful or manual runs", func() {
+			attempt, _ := pendingRetry(schedule, run("nightly-1", 5*time.Minute, PhaseCompleted), nextTime)
+			Expect(attempt).To(BeZero())
+
+			manual := run("nightly-manual-1", 5*time.Minute, PhaseFailed)
+			manual.Labels[LabelTrigger] = TriggerManual
+			attempt, _ = pendingRetry(schedule, manual, nextTime)
+			Expect(attempt).To(BeZero())
+		})
+
+		It("should not retry without retries in the failure policy", func() {
+			schedule.Spec.FailurePolicy.MaxRetries = 0
+			attempt, _ := pendingRetry(schedule, run("nightly-1", 5*time.Minute, PhaseFailed), nextTime)
+			Expect(attempt).To(BeZero())
+		})
+
+		It("should keep the scheduled time of the failed run", func() {
+			retry := newRetryDataMover(schedule, run("nightly-1", 5*time.Minute, PhaseFailed), 2)
+			Expect(retry.Name).To(Equal(fmt.Sprintf("nightly-%d-retry-1", scheduled.Unix())))
+			Expect(retry.Labels).To(HaveKeyWithValue(LabelScheduledTime, fmt.Sprintf("%d", scheduled.Unix())))
+			Expect(retry.Labels).To(HaveKeyWithValue(LabelAttempt, "2"))
+		})
+
+		It("should wait for the retry delay", func() {
+			r := &DataMoverScheduleReconciler{Recorder: record.NewFakeRecorder(10)}
+			runs := scheduleRuns{failed: []*datamoverv1alpha1.DataMover{run("nightly-1", 5*time.Minute, PhaseFailed)}}
+
+			result, err := r.retryFailedRun(context.Background(), schedule, runs, nil, nextTime, scheduled.Add(6*time.Minute))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(9 * time.Minute))
+		})
+
+		It("should wait for the backup window to retry", func() {
+			r := &DataMoverScheduleReconciler{Recorder: record.NewFakeRecorder(10)}
+			runs := scheduleRuns{failed: []*datamoverv1alpha1.DataMover{run("nightly-1", 5*time.Minute, PhaseFailed)}}
+			closed := &closedPeriod{reason: ReasonOutsideWindow, until: scheduled.Add(time.Hour)}
+
+			result, err := r.retryFailedRun(context.Background(), schedule, runs, closed, nextTime, scheduled.Add(20*time.Minute))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(40 * time.Minute))
+		})
+	})
+
+	Context("automatic suspension", func() {
+		var (
+			recorder *record.FakeRecorder
+			r        *DataMoverScheduleReconciler
+		)
+
+		// stored returns the schedule as written to the API server
+		stored := func() *datamoverv1alpha1.DataMoverSchedule {
+			var current datamoverv1alpha1.DataMoverSchedule
+			Expect(r.Get(context.Background(), client.ObjectKeyFromObject(schedule), &current)).To(Succeed())
+			return &current
+		}
+
+		BeforeEach(func() {
+			scheme := runtime.NewScheme()
+			Expect(datamoverv1alpha1.AddToScheme(scheme)).To(Succeed())
+			schedule.Spec.FailurePolicy.SuspendAfterFailures = &[]int32{3}[0]
+			recorder = record.NewFakeRecorder(10)
+			r = &DataMoverScheduleReconciler{
+				Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(schedule.DeepCopy()).Build(),
+				Recorder: recorder,
+			}
+		})
+
+		It("should suspend the schedule after too many consecutive failures", func() {
+			schedule.Status.ConsecutiveFailures = 3
+
+			Expect(r.applyFailurePolicy(context.Background(), schedule)).To(Succeed())
+			current := stored()
+			Expect(current.Spec.Suspend).To(BeTrue())
+			Expect(current.Annotations).To(HaveKey(AnnotationAutoSuspended))
+			Expect(schedule.Spec.Suspend).To(BeTrue())
+			Expect(isAutoSuspended(schedule)).To(BeTrue())
+			// The status computed so far is kept
+			Expect(schedule.Status.ConsecutiveFailures).To(Equal(int32(3)))
+			Expect(recorder.Events).To(Receive(And(
+				HavePrefix("Warning"),
+				ContainSubstring(EventAutoSuspended),
+				ContainSubstring("3 consecutive failed runs"),
+			)))
+		})
+
+		It("should not suspend the schedule below the threshold", func() {
+			schedule.Status.ConsecutiveFailures = 2
+
+			Expect(r.applyFailurePolicy(context.Background(), schedule)).To(Succeed())
+			Expect(stored().Spec.Suspend).To(BeFalse())
+			Expect(schedule.Annotations).NotTo(HaveKey(AnnotationAutoSuspended))
+			Expect(recorder.Events).NotTo(Receive())
+		})
+
+		It("should reset the failure count once resumed", func() {
+			schedule.Status.ConsecutiveFailures = 3
+			Expect(r.applyFailurePolicy(context.Background(), schedule)).To(Succeed())
+			Expect(recorder.Events).To(Receive())
+
+			// The user resumes the schedule
+			resumed := stored()
+			resumed.Spec.Suspend = false
+			Expect(r.Update(context.Background(), resumed)).To(Succeed())
+			resumed.Status = schedule.Status
+			schedule = resumed
+
+			Expect(r.applyFailurePolicy(context.Background(), schedule)).To(Succeed())
+			Expect(schedule.Status.ConsecutiveFailures).To(BeZero())
+			Expect(stored().Annotations).NotTo(HaveKey(AnnotationAutoSuspended))
+			Expect(schedule.Spec.Suspend).To(BeFalse())
+			Expect(recorder.Events).NotTo(Receive())
+		})
+	})
+
+	It("should explain an automatic suspension in the Suspended condition", func() {
+		schedule.Spec.Suspend = true
+		schedule.Annotations = map[string]string{AnnotationAutoSuspended: "2025-06-03T02:30:00Z"}
+		schedule.Status.ConsecutiveFailures = 3
+
+		setSuspendedCondition(schedule)
+		condition := meta.FindStatusCondition(schedule.Status.Conditions, ConditionSuspended)
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(ReasonTooManyFailures))
+		Expect(condition.Message).To(ContainSubstring("3 consecutive failed runs"))
+	})
+})
@@ -0,0 +1,130 @@
+package controller
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"time"
+
+	datamoverv1alpha1 "a-cup-of.coffee/datamover-operator/api/v1alpha1"
+)
+
+// cronField is the range of a field of a standard cron expression
+type cronField struct {
+	name     string
+	min, max int
+	// hashMax bounds the single value picked by a bare H, days of month stop at 28 so that
+	// they exist every month. Steps and explicit ranges use the whole field.
+	hashMax int
+}
+
+// cronFields lists the fields of a standard cron expression in order
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59, hashMax: 59},
+	{name: "hour", min: 0, max: 23, hashMax: 23},
+	{name: "day of month", min: 1, max: 31, hashMax: 28},
+	{name: "month", min: 1, max: 12, hashMax: 12},
+	{name: "day of week", min: 0, max: 6, hashMax: 6},
+}
+
+// hashSeed returns the seed of the H values and of the jitter offset of a schedule.
+// It only depends on the namespace and name, so a recreated schedule keeps its run times.
+func hashSeed(schedule *datamoverv1alpha1.DataMoverSchedule) string {
+	return schedule.Namespace + "/" + schedule.Name
+}
+
+// expandHashes replaces the H values of a standard cron expression by values hashed from the seed.
+// H picks a value in the range of the field, H(a-b) in the given range, and H/n or H(a-b)/n
+// a hashed start for the step. Macros and expressions without H are returned unchanged.
+func expandHashes(spec, seed string) (string, error) {
+	fields := strings.Fields(spec)
+	if strings.HasPrefix(spec, "@") || len(fields) != len(cronFields) || !strings.Contains(spec, "H") {
+		return spec, nil
+	}
+	for i, field := range cronFields {
+		values := strings.Split(fields[i], ",")
+		for j, value := range values {
+			if !strings.HasPrefix(value, "H") {
+				continue
+			}
+			expanded, err := field.expandHash(value, fieldHash(seed, i, j))
+			if err != nil {
+				return "", fmt.Errorf("invalid %s %q: %w", field.name, value, err)
+			}
+			values[j] = expanded
+		}
+		fields[i] = strings.Join(values, ",")
+	}
+	return strings.Join(fields, " "), nil
+}
+
+// fieldHash returns the hash of a value of a field of a schedule, which differs between fields
+// and between the values of a list such as H,H. The first value keeps the hash of the whole field.
+func fieldHash(seed string, field, value int) int {
+	h := fnv.New32a()
+	if value == 0 {
+		_, _ = fmt.Fprintf(h, "%s#%d", seed, field)
+	} else {
+		_, _ = fmt.Fprintf(h, "%s#%d#%d", seed, field, value)
+	}
+	return int(h.Sum32() & 0x7fffffff)
+}
+
+// expandHash replaces an H value of the field, as H, H(a-b), H/n or H(a-b)/n
+func (f cronField) expandHash(value string, hash int) (string, error) {
+	low, high := f.min, f.max
+	rest := strings.TrimPrefix(value, "H")
+	if rest == "" {
+		return strconv.Itoa(low + hash%(f.hashMax-low+1)), nil
+	}
+
+	if strings.HasPrefix(rest, "(") {
+		bounds, after, found := strings.Cut(rest[1:], ")")
+		if !found {
+			return "", fmt.Errorf("missing closing parenthesis")
+		}
+		lowValue, highValue, found := strings.Cut(bounds, "-")
+		if !found {
+			return "", fmt.Errorf("expected a range such as H(0-29)")
+		}
+		var err error
+		if low, err = strconv.Atoi(lowValue); err != nil {
+			return "", fmt.Errorf("invalid range start %q", lowValue)
+		}
+		if high, err = strconv.Atoi(highValue); err != nil {
+			return "", fmt.Errorf("invalid range end %q", highValue)
+		}
+		if low < f.min || high > f.max || low > high {
+			return "", fmt.Errorf("invalid range %d-%d, must be within %d-%d", low, high, f.min, f.max)
+		}
+		rest = after
+	}
+
+	if rest == "" {
+		return strconv.Itoa(low + hash%(high-low+1)), nil
+	}
+	stepValue, found := strings.CutPrefix(rest, "/")
+	if !found {
+		return "", fmt.Errorf("unexpected %q after H", rest)
+	}
+	step, err := strconv.Atoi(stepValue)
+	if err != nil || step <= 0 {
+		return "", fmt.Errorf("invalid step %q", stepValue)
+	}
+	// The step starts at a hashed value, which stays within the range
+	start := low + hash%min(step, high-low+1)
+	return fmt.Sprintf("%d-%d/%d", start, high, step), nil
+}
+
+// jitterOffset returns the stable delay of the runs of a schedule, below spec.jitter.
+// It is derived from the hash seed of the schedule and rounded to the second.
+func jitterOffset(schedule *datamoverv1alpha1.DataMoverSchedule) time.Duration {
+	if schedule.Spec.Jitter == nil || schedule.Spec.Jitter.Duration < time.Second {
+		return 0
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(hashSeed(schedule)))
+	seconds := uint64(schedule.Spec.Jitter.Duration / time.Second)
+	return time.Duration(h.Sum64()%seconds) * time.Second
+}
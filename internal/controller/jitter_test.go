@@ -0,0 +1,164 @@
+package controller
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	datamoverv1alpha1 "a-cup-of.coffee/datamover-operator/api/v1alpha1"
+)
+
+var _ = Describe("Schedule spreading", func() {
+	Context("H notation", func() {
+		It("should expand H to a stable value within the field", func() {
+			spec, err := expandHashes("H H * * *", "apps/nightly")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(expandHashes("H H * * *", "apps/nightly")).To(Equal(spec))
+
+			fields := strings.Fields(spec)
+			minute, err := strconv.Atoi(fields[0])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(minute).To(BeNumerically("<=", 59))
+			hour, err := strconv.Atoi(fields[1])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hour).To(BeNumerically("<=", 23))
+			Expect(fields[2:]).To(Equal([]string{"*", "*", "*"}))
+		})
+
+		It("should spread schedules with different seeds", func() {
+			minutes := map[string]bool{}
+			for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+				spec, err := expandHashes("H 2 * * *", "apps/"+name)
+				Expect(err).NotTo(HaveOccurred())
+				minutes[spec] = true
+			}
+			Expect(minutes).NotTo(HaveLen(1))
+		})
+
+		It("should hash each H of a list separately", func() {
+			distinct := false
+			for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+				spec, err := expandHashes("H,H * * * *", "apps/"+name)
+				Expect(err).NotTo(HaveOccurred())
+				minutes := strings.Split(strings.Fields(spec)[0], ",")
+				Expect(minutes).To(HaveLen(2))
+				distinct = distinct || minutes[0] != minutes[1]
+			}
+			Expect(distinct).To(BeTrue())
+
+			// The first H of a list keeps the value of a single H
+			single, err := expandHashes("H 2 * * *", "apps/nightly")
+			Expect(err).NotTo(HaveOccurred())
+			list, err := expandHashes("H,30 2 * * *", "apps/nightly")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(strings.Split(list, ",")[0]).To(Equal(strings.Fields(single)[0]))
+		})
+
+		It("should keep hashed values within the given range", func() {
+			for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+				spec, err := expandHashes("H(0-29) 3 * * *", "apps/"+name)
+				Expect(err).NotTo(HaveOccurred())
+				minute, err := strconv.Atoi(strings.Fields(spec)[0])
+				Expect(err).NotTo(HaveOccurred())
+				Expect(minute).To(BeNumerically("<=", 29))
+			}
+		})
+
+		It("should start steps at a hashed value", func() {
+			spec, err := expandHashes("H/15 * * * *", "apps/nightly")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(spec).To(MatchRegexp(`^([0-9]|1[0-4])-59/15 \* \* \* \*$`))
+
+			spec, err = expandHashes("H(10-20)/30 * * * *", "apps/nightly")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(spec).To(MatchRegexp(`^(1[0-9]|20)-20/30 \* \* \* \*$`))
+		})
+
+		It("should only pick days of month that exist every month", func() {
+			for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+				spec, err := expandHashes("0 0 H * *", "apps/"+name)
+				Expect(err).NotTo(HaveOccurred())
+				day, err := strconv.Atoi(strings.Fields(spec)[2])
+				Expect(err).NotTo(HaveOccurred())
+				Expect(day).To(BeNumerically(">=", 1))
+				Expect(day).To(BeNumerically("<=", 28))
+			}
+		})
+
+		It("should step through every day of month", func() {
+			spec, err := expandHashes("0 0 H/5 * *", "apps/nightly")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(spec).To(MatchRegexp(`^0 0 [1-5]-31/5 \* \*$`))
+		})
+
+		It("should leave other expressions unchanged", func() {
+			Expect(expandHashes("@daily", "apps/nightly")).To(Equal("@daily"))
+			Expect(expandHashes("0 3 * * THU", "apps/nightly")).To(Equal("0 3 * * THU"))
+		})
+
+		DescribeTable("should reject invalid H values",
+			func(spec string) {
+				_, err := expandHashes(spec, "apps/nightly")
+				Expect(err).To(HaveOccurred())
+			},
+			Entry("range out of bounds", "H(0-70) * * * *"),
+			Entry("reversed range", "H(30-10) * * * *"),
+			Entry("missing parenthesis", "H(0-29 * * * *"),
+			Entry("not a range", "H(5) * * * *"),
+			Entry("zero step", "H/0 * * * *"),
+			Entry("trailing characters", "Hx * * * *"),
+		)
+
+		It("should be accepted by parseSchedule", func() {
+			schedule, err := parseSchedule("H 2 * * *", &[]string{"UTC"}[0], "apps/nightly")
+			Expect(err).NotTo(HaveOccurred())
+			next := schedule.Next(time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC))
+			Expect(next.Hour()).To(Equal(2))
+			Expect(next.Day()).To(Equal(1))
+		})
+	})
+
+	Context("jitter", func() {
+		var schedule *datamoverv1alpha1.DataMoverSchedule
+
+		BeforeEach(func() {
+			schedule = &datamoverv1alpha1.DataMoverSchedule{
+				ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "apps", UID: types.UID("3b1f1c52")},
+			}
+		})
+
+		It("should not delay schedules without jitter", func() {
+			Expect(jitterOffset(schedule)).To(BeZero())
+		})
+
+		It("should derive a stable offset below the jitter", func() {
+			schedule.Spec.Jitter = &metav1.Duration{Duration: 30 * time.Minute}
+			offset := jitterOffset(schedule)
+			Expect(offset).To(BeNumerically("<", 30*time.Minute))
+			Expect(offset % time.Second).To(BeZero())
+			Expect(jitterOffset(schedule)).To(Equal(offset))
+		})
+
+		It("should spread schedules with different names", func() {
+			offsets := map[time.Duration]bool{}
+			for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+				schedule.Name = name
+				schedule.Spec.Jitter = &metav1.Duration{Duration: time.Hour}
+				offsets[jitterOffset(schedule)] = true
+			}
+			Expect(offsets).NotTo(HaveLen(1))
+		})
+
+		It("should keep the offset of a recreated schedule", func() {
+			schedule.Spec.Jitter = &metav1.Duration{Duration: time.Hour}
+			offset := jitterOffset(schedule)
+			schedule.UID = types.UID("9e4d27a0")
+			Expect(jitterOffset(schedule)).To(Equal(offset))
+		})
+	})
+})
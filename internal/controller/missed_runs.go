@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	datamoverv1alpha1 "a-cup-of.coffee/datamover-operator/api/v1alpha1"
+	"a-cup-of.coffee/datamover-operator/internal/metrics"
+)
+
+// EventMissedSchedule is emitted when scheduled runs did not start on time
+const EventMissedSchedule = "MissedSchedule"
+
+// Reasons of the scheduled runs that did not start, used as metric labels
+const (
+	skipReasonSuperseded       = "superseded"
+	skipReasonDeadlineExceeded = "deadline_exceeded"
+	skipReasonCatchUpLimit     = "catch_up_limit"
+	skipReasonForbidden        = "concurrency_forbidden"
+	skipReasonOutsideWindow    = "outside_window"
+	skipReasonBlackout         = "blackout"
+)
+
+// startingDeadline returns how late a missed run may start, if there is a deadline
+func startingDeadline(schedule *datamoverv1alpha1.DataMoverSchedule) *time.Duration {
+	if schedule.Spec.StartingDeadlineSeconds == nil {
+		return nil
+	}
+	deadline := time.Duration(*schedule.Spec.StartingDeadlineSeconds) * time.Second
+	return &deadline
+}
+
+// skipRuns records scheduled runs that did not start in the status and metrics
+func skipRuns(schedule *datamoverv1alpha1.DataMoverSchedule, runs int, last time.Time, reason string) {
+	schedule.Status.SkippedRuns += int32(runs)
+	schedule.Status.LastSkippedTime = &metav1.Time{Time: last}
+	metrics.RecordScheduleSkippedRuns(schedule.Name, reason, schedule.Namespace, float64(runs))
+}
+
+// dueRun returns the time of the run to start now, or the zero time if there is none.
+// Runs missed while the operator was unavailable are caught up: only the most recent one starts,
+// if within the starting deadline, and the others are recorded as skipped.
+func (r *DataMoverScheduleReconciler) dueRun(
+	ctx context.Context,
+	schedule *datamoverv1alpha1.DataMoverSchedule,
+	parsed *cronSchedule,
+	now time.Time,
+) time.Time {
+	logger := log.FromContext(ctx)
+
+	earliest := schedule.CreationTimestamp.Time
+	if schedule.Status.LastScheduleTime != nil {
+		earliest = schedule.Status.LastScheduleTime.Time
+	}
+
+	runs, tooMany := parsed.dueRuns(earliest, now)
+	if tooMany {
+		logger.Info("too many missed runs, waiting for the next one", "limit", maxMissedRuns)
+		r.Recorder.Eventf(schedule, corev1.EventTypeWarning, EventMissedSchedule,
+			"Missed more than %d runs, waiting for the next one", maxMissedRuns)
+		skipRuns(schedule, maxMissedRuns, now, skipReasonCatchUpLimit)
+		schedule.Status.LastScheduleTime = &metav1.Time{Time: now}
+		return time.Time{}
+	}
+	if len(runs) == 0 {
+		return time.Time{}
+	}
+
+	scheduledTime := runs[len(runs)-1]
+	if missed := runs[:len(runs)-1]; len(missed) > 0 {
+		logger.Info("missed scheduled runs, starting the most recent one",
+			"missed", len(missed), "scheduledTime", scheduledTime)
+		r.Recorder.Eventf(schedule, corev1.EventTypeWarning, EventMissedSchedule,
+			"Missed %d run(s) since %s, starting the run scheduled at %s",
+			len(missed), missed[0].Format(time.RFC3339), scheduledTime.Format(time.RFC3339))
+		skipRuns(schedule, len(missed), missed[len(missed)-1], skipReasonSuperseded)
+	}
+
+	if deadline := startingDeadline(schedule); deadline != nil && now.Sub(scheduledTime) > *deadline {
+		logger.Info("missed scheduled run, its starting deadline passed",
+			"scheduledTime", scheduledTime, "deadline", deadline.String())
+		r.Recorder.Eventf(schedule, corev1.EventTypeWarning, EventMissedSchedule,
+			"Missed the run scheduled at %s, its starting deadline of %s passed",
+			scheduledTime.Format(time.RFC3339), deadline.String())
+		skipRuns(schedule, 1, scheduledTime, skipReasonDeadlineExceeded)
+		schedule.Status.LastScheduleTime = &metav1.Time{Time: scheduledTime}
+		return time.Time{}
+	}
+	return scheduledTime
+}
@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	datamoverv1alpha1 "a-cup-of.coffee/datamover-operator/api/v1alpha1"
+)
+
+var _ = Describe("Missed runs", func() {
+	var (
+		recorder *record.FakeRecorder
+		r        *DataMoverScheduleReconciler
+		hourly   *cronSchedule
+		now      time.Time
+	)
+
+	BeforeEach(func() {
+		recorder = record.NewFakeRecorder(10)
+		r = &DataMoverScheduleReconciler{Recorder: recorder}
+		var err error
+		hourly, err = parseSchedule("0 * * * *", &[]string{"UTC"}[0], "")
+		Expect(err).NotTo(HaveOccurred())
+		now = time.Date(2025, 6, 3, 9, 30, 0, 0, time.UTC)
+	})
+
+	scheduleLastRunAt := func(last time.Time) *datamoverv1alpha1.DataMoverSchedule {
+		return &datamoverv1alpha1.DataMoverSchedule{
+			ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "default"},
+			Status:     datamoverv1alpha1.DataMoverScheduleStatus{LastScheduleTime: &metav1.Time{Time: last}},
+		}
+	}
+
+	It("should start the run due now", func() {
+		schedule := scheduleLastRunAt(time.Date(2025, 6, 3, 8, 0, 0, 0, time.UTC))
+
+		scheduledTime := r.dueRun(context.Background(), schedule, hourly, now)
+		Expect(scheduledTime).To(Equal(time.Date(2025, 6, 3, 9, 0, 0, 0, time.UTC)))
+		Expect(schedule.Status.SkippedRuns).To(BeZero())
+	})
+
+	It("should start the most recent missed run and skip the older ones", func() {
+		schedule := scheduleLastRunAt(time.Date(2025, 6, 3, 6, 0, 0, 0, time.UTC))
+
+		scheduledTime := r.dueRun(context.Background(), schedule, hourly, now)
+		Expect(scheduledTime).To(Equal(time.Date(2025, 6, 3, 9, 0, 0, 0, time.UTC)))
+		Expect(schedule.Status.SkippedRuns).To(Equal(int32(2)))
+		Expect(schedule.Status.LastSkippedTime.Time).To(Equal(time.Date(2025, 6, 3, 8, 0, 0, 0, time.UTC)))
+		Expect(recorder.Events).To(Receive(ContainSubstring(EventMissedSchedule)))
+	})
+
+	It("should skip a missed run past its starting deadline", func() {
+		schedule := scheduleLastRunAt(time.Date(2025, 6, 3, 8, 0, 0, 0, time.UTC))
+		schedule.Spec.StartingDeadlineSeconds = &[]int64{600}[0]
+
+		scheduledTime := r.dueRun(context.Background(), schedule, hourly, now)
+		Expect(scheduledTime).To(BeZero())
+		Expect(schedule.Status.SkippedRuns).To(Equal(int32(1)))
+		Expect(schedule.Status.LastScheduleTime.Time).To(Equal(time.Date(2025, 6, 3, 9, 0, 0, 0, time.UTC)))
+
+		// The skipped run is not looked at again
+		Expect(r.dueRun(context.Background(), schedule, hourly, now)).To(BeZero())
+		Expect(schedule.Status.SkippedRuns).To(Equal(int32(1)))
+	})
+})
@@ -0,0 +1,175 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"unicode/utf8"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	datamoverv1alpha1 "a-cup-of.coffee/datamover-operator/api/v1alpha1"
+)
+
+// Reasons used when the mover fails
+const (
+	ReasonAuthenticationFailed = "AuthenticationFailed"
+	ReasonNotFound             = "NotFound"
+	ReasonNetworkError         = "NetworkError"
+	ReasonPartialTransfer      = "PartialTransfer"
+)
+
+// moverLogTailLines is the number of log lines of a failed mover kept in the status
+const moverLogTailLines int64 = 20
+
+// maxMoverErrorLength bounds the size of the messages and logs stored in the status
+const maxMoverErrorLength = 4096
+
+// Exit codes documented by rclone
+const (
+	rcloneExitDirNotFound      = 3
+	rcloneExitFileNotFound     = 4
+	rcloneExitTemporaryError   = 5
+	rcloneExitNoRetryError     = 6
+	rcloneExitTransferExceeded = 8
+	rcloneExitDurationExceeded = 10
+)
+
+// moverFailurePatterns maps substrings of rclone errors to a failure reason.
+// Patterns are matched in order against the lower-cased message and logs.
+var moverFailurePatterns = []struct {
+	reason   string
+	patterns []string
+}{
+	{ReasonAuthenticationFailed, []string{
+		"accessdenied", "access denied", "invalidaccesskeyid", "signaturedoesnotmatch", "forbidden",
+		"unauthorized", "status code: 401", "status code: 403",
+	}},
+	{ReasonQuotaExceeded, []string{
+		"quotaexceeded", "quota exceeded", "storage full", "storagefull", "insufficient storage",
+		"no space left", "entitytoolarge",
+	}},
+	// Only rclone's own wording, as "not found" alone also matches shell errors such as "command not found"
+	{ReasonNotFound, []string{
+		"nosuchbucket", "no such bucket", "bucket not found", "containernotfound", "directory not found",
+		"object not found", "nosuchkey",
+	}},
+	{ReasonNetworkError, []string{
+		"connection refused", "connection reset", "no such host", "i/o timeout", "timeout awaiting",
+		"network is unreachable", "tls handshake", "x509", "unexpected eof",
+	}},
+}
+
+// classifyMoverFailure returns the failure reason matching the exit code and output of the mover.
+// Messages are checked first since rclone reports most remote errors with a generic exit code.
+func classifyMoverFailure(exitCode int32, output string) string {
+	output = strings.ToLower(output)
+	for _, class := range moverFailurePatterns {
+		for _, pattern := range class.patterns {
+			if strings.Contains(output, pattern) {
+				return class.reason
+			}
+		}
+	}
+
+	switch exitCode {
+	case rcloneExitDirNotFound, rcloneExitFileNotFound:
+		return ReasonNotFound
+	case rcloneExitTemporaryError:
+		return ReasonNetworkError
+	case rcloneExitNoRetryError, rcloneExitTransferExceeded, rcloneExitDurationExceeded:
+		return ReasonPartialTransfer
+	default:
+		return ReasonJobFailed
+	}
+}
+
+// getMoverError captures the failure of the most recent failed pod of the mover Job.
+// Returns nil when no failed pod is left to inspect.
+func (r *DataMoverReconciler) getMoverError(
+	ctx context.Context,
+	job *batchv1.Job,
+) (*datamoverv1alpha1.MoverError, error) {
+	var pods corev1.PodList
+	if err := r.uncachedReader().List(ctx, &pods, client.InNamespace(job.Namespace),
+		client.MatchingLabels{"job-name": job.Name}); err != nil {
+		return nil, err
+	}
+
+	var pod *corev1.Pod
+	var terminated *corev1.ContainerStateTerminated
+	for i := range pods.Items {
+		for _, status := range pods.Items[i].Status.ContainerStatuses {
+			state := status.State.Terminated
+			if status.Name != moverContainerName || state == nil || state.ExitCode == 0 {
+				continue
+			}
+			if terminated == nil || terminated.FinishedAt.Before(&state.FinishedAt) {
+				pod, terminated = &pods.Items[i], state
+			}
+		}
+	}
+	if terminated == nil {
+		return nil, nil
+	}
+
+	moverError := &datamoverv1alpha1.MoverError{
+		ExitCode:   terminated.ExitCode,
+		Message:    terminationError(terminated),
+		Logs:       r.moverLogTail(ctx, pod),
+		PodName:    pod.Name,
+		FinishedAt: terminated.FinishedAt.DeepCopy(),
+	}
+	moverError.Reason = classifyMoverFailure(moverError.ExitCode, moverError.Message+"\n"+moverError.Logs)
+	return moverError, nil
+}
+
+// terminationError extracts the error reported by the mover entrypoint.
+// The raw message is kept when the mover could not write its summary, in which case
+// the kubelet falls back to the end of the container logs.
+func terminationError(terminated *corev1.ContainerStateTerminated) string {
+	var result moverResult
+	if err := json.Unmarshal([]byte(terminated.Message), &result); err == nil && result.Error != "" {
+		return result.Error
+	}
+
+	message := strings.TrimSpace(terminated.Message)
+	if message == "" {
+		message = terminated.Reason
+	}
+	return truncateTail(message, maxMoverErrorLength)
+}
+
+// moverLogTail returns the last lines logged by the mover container.
+// Logs are best effort: the pod may already be gone or its node unreachable.
+func (r *DataMoverReconciler) moverLogTail(ctx context.Context, pod *corev1.Pod) string {
+	if r.Clientset == nil {
+		return ""
+	}
+
+	tailLines := moverLogTailLines
+	logs, err := r.Clientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container: moverContainerName,
+		TailLines: &tailLines,
+	}).DoRaw(ctx)
+	if err != nil {
+		log.FromContext(ctx).V(1).Info("Unable to read mover logs", "pod", pod.Name, "error", err.Error())
+		return ""
+	}
+	return truncateTail(strings.TrimSpace(string(logs)), maxMoverErrorLength)
+}
+
+// truncateTail keeps at most the last limit bytes of s, where errors are usually reported
+func truncateTail(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	start := len(s) - limit
+	for start < len(s) && !utf8.RuneStart(s[start]) {
+		start++
+	}
+	return s[start:]
+}
@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	datamoverv1alpha1 "a-cup-of.coffee/datamover-operator/api/v1alpha1"
+)
+
+var _ = Describe("Mover failures", func() {
+	DescribeTable("should classify rclone failures",
+		func(exitCode int, output, reason string) {
+			Expect(classifyMoverFailure(int32(exitCode), output)).To(Equal(reason))
+		},
+		Entry("invalid credentials", 7,
+			`Failed to lsd: InvalidAccessKeyId: The Access Key Id you provided does not exist in our records.`,
+			ReasonAuthenticationFailed),
+		Entry("access denied", 2, `Failed to sync: AccessDenied: Access Denied status code: 403`,
+			ReasonAuthenticationFailed),
+		Entry("quota", 2, `Failed to copy: XMinioStorageFull: Storage backend has reached its minimum free drive threshold`,
+			ReasonQuotaExceeded),
+		Entry("missing bucket", 3, `Failed to lsd: NoSuchBucket: The specified bucket does not exist`, ReasonNotFound),
+		Entry("missing directory", 1, `Failed to sync: directory not found`, ReasonNotFound),
+		Entry("unrelated not found with exit code", 5, "/entrypoint.sh: line 42: jq: command not found",
+			ReasonNetworkError),
+		Entry("unrelated not found", 1, "/entrypoint.sh: line 42: jq: command not found", ReasonJobFailed),
+		Entry("unreachable endpoint", 1, `dial tcp 10.0.0.1:443: connect: connection refused`, ReasonNetworkError),
+		Entry("not found exit code", 3, "Rclone sync failed.", ReasonNotFound),
+		Entry("temporary error exit code", 5, "Rclone sync failed.", ReasonNetworkError),
+		Entry("transfer limit exit code", 8, "Rclone copy failed.", ReasonPartialTransfer),
+		Entry("unknown", 1, "Rclone sync failed.", ReasonJobFailed),
+	)
+
+	It("should read the error reported by the mover", func() {
+		Expect(terminationError(&corev1.ContainerStateTerminated{
+			Message: `{"error":"Rclone sync failed.","exitCode":7}`,
+		})).To(Equal("Rclone sync failed."))
+	})
+
+	It("should keep the raw message when the mover did not report an error", func() {
+		Expect(terminationError(&corev1.ContainerStateTerminated{
+			Message: "last log line\n",
+		})).To(Equal("last log line"))
+		Expect(terminationError(&corev1.ContainerStateTerminated{Reason: "OOMKilled"})).To(Equal("OOMKilled"))
+	})
+
+	It("should keep the end of long messages on a rune boundary", func() {
+		Expect(truncateTail("abcdef", 3)).To(Equal("def"))
+		Expect(truncateTail("a✅b", 3)).To(Equal("b"))
+		Expect(truncateTail("short", 10)).To(Equal("short"))
+	})
+
+	It("should report each failed attempt of a retrying Job once", func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		job := &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "backup-mover-1234abcd", Namespace: "default",
+				Labels: map[string]string{LabelDataMoverUID: "1234abcd", LabelComponent: ComponentMover},
+			},
+			Status: batchv1.JobStatus{Active: 1, Failed: 1},
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(job).Build()
+		recorder := record.NewFakeRecorder(10)
+		r := &DataMoverReconciler{Client: c, Recorder: recorder}
+		dm := &datamoverv1alpha1.DataMover{
+			ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: "default", UID: "1234abcd"},
+			Status:     datamoverv1alpha1.DataMoverStatus{Phase: PhaseCreatingPod},
+		}
+
+		_, err := r.waitForJobCompletion(context.Background(), dm)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).To(Receive(ContainSubstring(EventJobRetrying)))
+		Expect(dm.Status.FailedAttempts).To(Equal(int32(1)))
+
+		_, err = r.waitForJobCompletion(context.Background(), dm)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).NotTo(Receive())
+
+		job.Status.Failed = 2
+		Expect(c.Status().Update(context.Background(), job)).To(Succeed())
+		_, err = r.waitForJobCompletion(context.Background(), dm)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).To(Receive(ContainSubstring("after 2 failed attempts")))
+	})
+})
@@ -0,0 +1,212 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	datamoverv1alpha1 "a-cup-of.coffee/datamover-operator/api/v1alpha1"
+	"a-cup-of.coffee/datamover-operator/internal/metrics"
+)
+
+const (
+	// ConditionMoverStuck reports whether the mover pod cannot start
+	ConditionMoverStuck = "MoverStuck"
+
+	ReasonImagePullFailed = "ImagePullFailed"
+	ReasonUnschedulable   = "Unschedulable"
+	ReasonMountFailed     = "MountFailed"
+	ReasonMissingConfig   = "MissingConfig"
+	ReasonPodProgressing  = "PodProgressing"
+)
+
+// Kubelet event reasons reported while volumes of a pod cannot be attached or mounted
+var mountFailureEventReasons = []string{"FailedMount", "FailedAttachVolume"}
+
+// podDiagnosis explains why a mover pod cannot start
+type podDiagnosis struct {
+	reason  string
+	message string
+}
+
+// diagnosePod classifies the status of a mover pod, returning nil when nothing prevents it from running
+func diagnosePod(pod *corev1.Pod) *podDiagnosis {
+	if pod.Status.Phase != corev1.PodPending && pod.Status.Phase != corev1.PodRunning {
+		return nil
+	}
+
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionFalse &&
+			condition.Reason == corev1.PodReasonUnschedulable {
+			return &podDiagnosis{
+				reason:  ReasonUnschedulable,
+				message: fmt.Sprintf("Pod %s cannot be scheduled: %s", pod.Name, condition.Message),
+			}
+		}
+	}
+
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...),
+		pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		waiting := status.State.Waiting
+		if waiting == nil {
+			continue
+		}
+		switch waiting.Reason {
+		case "ErrImagePull", "ImagePullBackOff", "InvalidImageName", "ErrImageNeverPull":
+			return &podDiagnosis{
+				reason:  ReasonImagePullFailed,
+				message: fmt.Sprintf("Pod %s cannot pull image %s: %s", pod.Name, status.Image, waiting.Message),
+			}
+		case "CreateContainerConfigError":
+			return &podDiagnosis{
+				reason:  ReasonMissingConfig,
+				message: fmt.Sprintf("Pod %s cannot be configured: %s", pod.Name, waiting.Message),
+			}
+		}
+	}
+
+	return nil
+}
+
+// isContainerCreating reports whether a pod is scheduled but its containers have not started yet
+func isContainerCreating(pod *corev1.Pod) bool {
+	if pod.Status.Phase != corev1.PodPending || pod.Spec.NodeName == "" {
+		return false
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Waiting == nil || status.State.Waiting.Reason != "ContainerCreating" {
+			return false
+		}
+	}
+	return true
+}
+
+// diagnoseMoverPods returns why the pods of the mover Job cannot start, if any
+func (r *DataMoverReconciler) diagnoseMoverPods(
+	ctx context.Context,
+	job *batchv1.Job,
+) (*podDiagnosis, error) {
+	var pods corev1.PodList
+	if err := r.uncachedReader().List(ctx, &pods, client.InNamespace(job.Namespace),
+		client.MatchingLabels{"job-name": job.Name}); err != nil {
+		return nil, err
+	}
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if diagnosis := diagnosePod(pod); diagnosis != nil {
+			return diagnosis, nil
+		}
+
+		// Volume errors are only reported through kubelet events
+		if !isContainerCreating(pod) {
+			continue
+		}
+		for _, reason := range mountFailureEventReasons {
+			event, err := r.findLatestEvent(ctx, pod.Namespace, pod.UID, reason)
+			if err != nil {
+				return nil, err
+			}
+			if event != nil {
+				return &podDiagnosis{
+					reason:  ReasonMountFailed,
+					message: fmt.Sprintf("Pod %s cannot mount its volumes: %s", pod.Name, event.Message),
+				}, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// checkStuckMover reports stuck mover pods in the MoverStuck condition and, once they stayed
+// stuck longer than the grace period, deletes the mover Job and fails the DataMover.
+// It reports whether the reconcile was handled.
+func (r *DataMoverReconciler) checkStuckMover(
+	ctx context.Context,
+	dm *datamoverv1alpha1.DataMover,
+	job *batchv1.Job,
+) (ctrl.Result, bool, error) {
+	logger := log.FromContext(ctx)
+
+	diagnosis, err := r.diagnoseMoverPods(ctx, job)
+	if err != nil {
+		// Diagnosis is best effort, keep waiting for the Job
+		logger.Error(err, "Failed to inspect mover pods", "jobName", job.Name)
+		metrics.RecordError("pod_list_failed", PhaseCreatingPod, dm.Namespace)
+		return ctrl.Result{}, false, nil
+	}
+
+	if diagnosis == nil {
+		if !clearMoverStuck(dm) {
+			return ctrl.Result{}, false, nil
+		}
+		logger.Info("Mover pod is no longer stuck", "jobName", job.Name)
+	} else {
+		// Messages change on every back-off or retry, only a new reason is a new problem
+		previous := meta.FindStatusCondition(dm.Status.Conditions, ConditionMoverStuck)
+		newReason := previous == nil || previous.Status != metav1.ConditionTrue || previous.Reason != diagnosis.reason
+		changed := meta.SetStatusCondition(&dm.Status.Conditions, metav1.Condition{
+			Type:               ConditionMoverStuck,
+			Status:             metav1.ConditionTrue,
+			Reason:             diagnosis.reason,
+			Message:            diagnosis.message,
+			ObservedGeneration: dm.Generation,
+		})
+		if newReason {
+			logger.Info("Mover pod is stuck", "reason", diagnosis.reason, "message", diagnosis.message)
+			metrics.RecordErrorWithReason("mover_pod_stuck", metricReason(diagnosis.reason),
+				PhaseCreatingPod, dm.Namespace)
+			r.Recorder.Event(dm, corev1.EventTypeWarning, diagnosis.reason, diagnosis.message)
+		}
+
+		stuck := meta.FindStatusCondition(dm.Status.Conditions, ConditionMoverStuck)
+		if grace := dm.Spec.StuckPodGracePeriod; grace != nil &&
+			time.Since(stuck.LastTransitionTime.Time) >= grace.Duration {
+			// A pod starting later would move the data of a DataMover already reported as Failed
+			if err := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil &&
+				!errors.IsNotFound(err) {
+				logger.Error(err, "Failed to delete stuck mover Job", "jobName", job.Name)
+				metrics.RecordError("job_delete_failed", PhaseCreatingPod, dm.Namespace)
+				return ctrl.Result{}, true, err
+			}
+			logger.Info("Deleted stuck mover Job", "jobName", job.Name)
+			r.afterStatusWrite(dm, func() {
+				metrics.RecordPodCreationOperation("failure", dm.Namespace)
+			})
+			r.failDataMover(dm, diagnosis.reason,
+				fmt.Sprintf("Mover pod stuck for more than %s: %s", grace.Duration, diagnosis.message))
+			return ctrl.Result{}, true, nil
+		}
+		if !changed {
+			return ctrl.Result{}, false, nil
+		}
+	}
+
+	return ctrl.Result{RequeueAfter: 15 * time.Second}, true, nil
+}
+
+// clearMoverStuck resets a MoverStuck condition left by a pod that recovered.
+// It reports whether the status changed.
+func clearMoverStuck(dm *datamoverv1alpha1.DataMover) bool {
+	if !meta.IsStatusConditionTrue(dm.Status.Conditions, ConditionMoverStuck) {
+		return false
+	}
+	return meta.SetStatusCondition(&dm.Status.Conditions, metav1.Condition{
+		Type:               ConditionMoverStuck,
+		Status:             metav1.ConditionFalse,
+		Reason:             ReasonPodProgressing,
+		Message:            "The mover pod is no longer stuck",
+		ObservedGeneration: dm.Generation,
+	})
+}
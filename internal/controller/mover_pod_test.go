@@ -0,0 +1,148 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	datamoverv1alpha1 "a-cup-of.coffee/datamover-operator/api/v1alpha1"
+	"a-cup-of.coffee/datamover-operator/internal/metrics"
+)
+
+var _ = Describe("Mover pod diagnosis", func() {
+	waitingPod := func(reason, message string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "mover"},
+			Spec:       corev1.PodSpec{NodeName: "node-1"},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodPending,
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name:  moverContainerName,
+					Image: "ghcr.io/qjoly/datamover-rclone:latest",
+					State: corev1.ContainerState{
+						Waiting: &corev1.ContainerStateWaiting{Reason: reason, Message: message},
+					},
+				}},
+			},
+		}
+	}
+
+	It("should report image pull errors", func() {
+		diagnosis := diagnosePod(waitingPod("ImagePullBackOff", "Back-off pulling image"))
+		Expect(diagnosis).NotTo(BeNil())
+		Expect(diagnosis.reason).To(Equal(ReasonImagePullFailed))
+		Expect(diagnosis.message).To(ContainSubstring("ghcr.io/qjoly/datamover-rclone:latest"))
+	})
+
+	It("should report missing secrets", func() {
+		diagnosis := diagnosePod(waitingPod("CreateContainerConfigError", `secret "s3-credentials" not found`))
+		Expect(diagnosis).NotTo(BeNil())
+		Expect(diagnosis.reason).To(Equal(ReasonMissingConfig))
+	})
+
+	It("should report unschedulable pods", func() {
+		pod := &corev1.Pod{
+			Status: corev1.PodStatus{
+				Phase: corev1.PodPending,
+				Conditions: []corev1.PodCondition{{
+					Type:    corev1.PodScheduled,
+					Status:  corev1.ConditionFalse,
+					Reason:  corev1.PodReasonUnschedulable,
+					Message: "0/3 nodes are available",
+				}},
+			},
+		}
+		diagnosis := diagnosePod(pod)
+		Expect(diagnosis).NotTo(BeNil())
+		Expect(diagnosis.reason).To(Equal(ReasonUnschedulable))
+	})
+
+	It("should leave progressing pods alone", func() {
+		pod := waitingPod("ContainerCreating", "")
+		Expect(diagnosePod(pod)).To(BeNil())
+		Expect(isContainerCreating(pod)).To(BeTrue())
+
+		pod.Spec.NodeName = ""
+		Expect(isContainerCreating(pod)).To(BeFalse())
+	})
+
+	It("should only report a stuck pod again when the reason changes", func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+
+		const namespace = "stuck-pods"
+		job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "mover-job", Namespace: namespace}}
+		pod := waitingPod("ErrImagePull", "rpc error: code = NotFound")
+		pod.Namespace = namespace
+		pod.Labels = map[string]string{"job-name": job.Name}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pod).Build()
+		recorder := record.NewFakeRecorder(10)
+		r := &DataMoverReconciler{Client: c, Recorder: recorder}
+		dm := &datamoverv1alpha1.DataMover{ObjectMeta: metav1.ObjectMeta{Name: "stuck", Namespace: namespace}}
+		stuckCount := func() float64 {
+			return testutil.ToFloat64(metrics.DataMoverErrorsTotal.WithLabelValues("mover_pod_stuck", PhaseCreatingPod, namespace))
+		}
+
+		_, handled, err := r.checkStuckMover(context.Background(), dm, job)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(handled).To(BeTrue())
+		Expect(recorder.Events).To(Receive(ContainSubstring(ReasonImagePullFailed)))
+		Expect(stuckCount()).To(Equal(1.0))
+
+		// The kubelet backs off with a new message for the same problem
+		pod.Status.ContainerStatuses[0].State.Waiting = &corev1.ContainerStateWaiting{
+			Reason: "ImagePullBackOff", Message: "Back-off pulling image",
+		}
+		Expect(c.Status().Update(context.Background(), pod)).To(Succeed())
+		_, _, err = r.checkStuckMover(context.Background(), dm, job)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).NotTo(Receive())
+		Expect(stuckCount()).To(Equal(1.0))
+
+		pod.Status.ContainerStatuses[0].State.Waiting = &corev1.ContainerStateWaiting{
+			Reason: "CreateContainerConfigError", Message: `secret "storage-credentials" not found`,
+		}
+		Expect(c.Status().Update(context.Background(), pod)).To(Succeed())
+		_, _, err = r.checkStuckMover(context.Background(), dm, job)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).To(Receive(ContainSubstring(ReasonMissingConfig)))
+		Expect(stuckCount()).To(Equal(2.0))
+	})
+
+	It("should delete the mover Job when failing a stuck DataMover", func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+
+		const namespace = "stuck-pods-grace"
+		job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "mover-job", Namespace: namespace}}
+		pod := waitingPod("ImagePullBackOff", "Back-off pulling image")
+		pod.Namespace = namespace
+		pod.Labels = map[string]string{"job-name": job.Name}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(job, pod).Build()
+		r := &DataMoverReconciler{Client: c, Recorder: record.NewFakeRecorder(10)}
+		dm := &datamoverv1alpha1.DataMover{
+			ObjectMeta: metav1.ObjectMeta{Name: "stuck", Namespace: namespace},
+			Spec:       datamoverv1alpha1.DataMoverSpec{StuckPodGracePeriod: &metav1.Duration{}},
+			Status:     datamoverv1alpha1.DataMoverStatus{Phase: PhaseCreatingPod},
+		}
+
+		_, handled, err := r.checkStuckMover(context.Background(), dm, job)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(handled).To(BeTrue())
+		Expect(dm.Status.Phase).To(Equal(PhaseFailed))
+
+		err = c.Get(context.Background(), client.ObjectKeyFromObject(job), &batchv1.Job{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+})
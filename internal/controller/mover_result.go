@@ -0,0 +1,128 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	datamoverv1alpha1 "a-cup-of.coffee/datamover-operator/api/v1alpha1"
+)
+
+// moverContainerName is the name of the rclone container in the mover Job
+const moverContainerName = "rclone"
+
+// moverResult is the JSON summary written by the mover entrypoint to its termination message
+type moverResult struct {
+	Mode                 string `json:"mode"`
+	MissingOnDestination int32  `json:"missingOnDestination,omitempty"`
+	MissingOnSource      int32  `json:"missingOnSource,omitempty"`
+	Differing            int32  `json:"differing,omitempty"`
+	Errors               int32  `json:"errors,omitempty"`
+
+	// Error is set instead of the summary when the mover failed
+	Error string `json:"error,omitempty"`
+
+	Verification *moverVerification `json:"verification,omitempty"`
+	Estimate     *moverEstimate     `json:"estimate,omitempty"`
+}
+
+// moverEstimate is the transfer estimate computed by the mover during a dry run
+type moverEstimate struct {
+	Bytes   int64 `json:"bytes"`
+	Files   int64 `json:"files"`
+	Changes int64 `json:"changes"`
+}
+
+// moverVerification is the outcome of the post-transfer verification run by the mover
+type moverVerification struct {
+	Mismatches int32  `json:"mismatches"`
+	Manifest   string `json:"manifest,omitempty"`
+}
+
+// checkResult converts the mover summary into the status representation of a Check run
+func (m *moverResult) checkResult() *datamoverv1alpha1.CheckResult {
+	return &datamoverv1alpha1.CheckResult{
+		MissingOnDestination: m.MissingOnDestination,
+		MissingOnSource:      m.MissingOnSource,
+		Differing:            m.Differing,
+		Errors:               m.Errors,
+	}
+}
+
+// verificationResult converts the mover verification into its status representation
+func (m *moverResult) verificationResult() *datamoverv1alpha1.VerificationResult {
+	if m.Verification == nil {
+		return nil
+	}
+	return &datamoverv1alpha1.VerificationResult{
+		Mismatches: m.Verification.Mismatches,
+		Manifest:   m.Verification.Manifest,
+	}
+}
+
+// transferEstimate converts the mover estimate into its status representation
+func (m *moverResult) transferEstimate() *datamoverv1alpha1.TransferEstimate {
+	if m.Estimate == nil {
+		return nil
+	}
+	return &datamoverv1alpha1.TransferEstimate{
+		Bytes:   m.Estimate.Bytes,
+		Files:   m.Estimate.Files,
+		Changes: m.Estimate.Changes,
+	}
+}
+
+// differences returns the total number of differences reported by a Check run
+func (m *moverResult) differences() int32 {
+	return m.MissingOnDestination + m.MissingOnSource + m.Differing + m.Errors
+}
+
+// parseMoverResult parses the summary written by the mover to its termination message.
+// Messages longer than the termination message limit are truncated by the kubelet and
+// fail to parse like any malformed message.
+func parseMoverResult(message string) (*moverResult, error) {
+	message = strings.TrimSpace(message)
+	if message == "" {
+		return nil, fmt.Errorf("empty termination message")
+	}
+	var result moverResult
+	if err := json.Unmarshal([]byte(message), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// getMoverResult reads the summary reported by the successful pod of the mover Job
+func (r *DataMoverReconciler) getMoverResult(
+	ctx context.Context,
+	namespace, jobName string,
+) (*moverResult, error) {
+	var pods corev1.PodList
+	if err := r.uncachedReader().List(ctx, &pods, client.InNamespace(namespace),
+		client.MatchingLabels{"job-name": jobName}); err != nil {
+		return nil, err
+	}
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase != corev1.PodSucceeded {
+			continue
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.Name != moverContainerName || status.State.Terminated == nil {
+				continue
+			}
+			result, err := parseMoverResult(status.State.Terminated.Message)
+			if err != nil {
+				return nil, fmt.Errorf("invalid mover result in pod %s: %w", pod.Name, err)
+			}
+			return result, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no succeeded pod with a mover result found for job %s", jobName)
+}
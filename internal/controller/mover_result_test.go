@@ -0,0 +1,158 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	datamoverv1alpha1 "a-cup-of.coffee/datamover-operator/api/v1alpha1"
+	"a-cup-of.coffee/datamover-operator/internal/metrics"
+)
+
+var _ = Describe("Mover results", func() {
+	DescribeTable("should parse the termination message of the mover",
+		func(message string, expected *moverResult) {
+			result, err := parseMoverResult(message)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(expected))
+		},
+		Entry("sync summary", `{"mode":"Sync"}`, &moverResult{Mode: "Sync"}),
+		Entry("check summary",
+			`{"mode":"Check","missingOnDestination":2,"missingOnSource":1,"differing":3,"errors":0}`,
+			&moverResult{Mode: "Check", MissingOnDestination: 2, MissingOnSource: 1, Differing: 3}),
+		Entry("trailing newline", "{\"mode\":\"Copy\"}\n", &moverResult{Mode: "Copy"}),
+		Entry("verification",
+			`{"mode":"Sync","verification":{"mismatches":1,"manifest":"s3:bucket/app/.datamover-manifest.sha256"}}`,
+			&moverResult{Mode: "Sync", Verification: &moverVerification{
+				Mismatches: 1, Manifest: "s3:bucket/app/.datamover-manifest.sha256",
+			}}),
+	)
+
+	DescribeTable("should reject invalid termination messages",
+		func(message string) {
+			_, err := parseMoverResult(message)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("empty", ""),
+		Entry("blank", " \n"),
+		Entry("malformed", "Rclone sync failed."),
+		Entry("truncated", `{"mode":"Check","missingOnDestination":2,"missingOn`),
+	)
+
+	Context("check results", func() {
+		var (
+			recorder *record.FakeRecorder
+			r        *DataMoverReconciler
+			dm       *datamoverv1alpha1.DataMover
+		)
+
+		BeforeEach(func() {
+			recorder = record.NewFakeRecorder(10)
+			r = &DataMoverReconciler{Recorder: recorder}
+			dm = &datamoverv1alpha1.DataMover{
+				ObjectMeta: metav1.ObjectMeta{Name: "drift", Namespace: "mover-results", Generation: 2},
+			}
+		})
+
+		DescribeTable("should map the differences into the status and the InSync condition",
+			func(result moverResult, status metav1.ConditionStatus, reason string) {
+				r.recordCheckResult(context.Background(), dm, &result)
+
+				Expect(dm.Status.CheckResult).To(Equal(&datamoverv1alpha1.CheckResult{
+					MissingOnDestination: result.MissingOnDestination,
+					MissingOnSource:      result.MissingOnSource,
+					Differing:            result.Differing,
+					Errors:               result.Errors,
+				}))
+				condition := meta.FindStatusCondition(dm.Status.Conditions, ConditionInSync)
+				Expect(condition).NotTo(BeNil())
+				Expect(condition.Status).To(Equal(status))
+				Expect(condition.Reason).To(Equal(reason))
+				Expect(condition.ObservedGeneration).To(Equal(int64(2)))
+				Expect(testutil.ToFloat64(metrics.DataMoverCheckDifferences.WithLabelValues("drift", "mover-results"))).
+					To(Equal(float64(result.differences())))
+			},
+			Entry("in sync", moverResult{Mode: "Check"}, metav1.ConditionTrue, ReasonNoDifferences),
+			Entry("drift", moverResult{Mode: "Check", MissingOnDestination: 2, Differing: 1},
+				metav1.ConditionFalse, ReasonDriftDetected),
+			Entry("errors only", moverResult{Mode: "Check", Errors: 1}, metav1.ConditionFalse, ReasonDriftDetected),
+		)
+
+		It("should describe the drift in a Warning event", func() {
+			r.recordCheckResult(context.Background(), dm, &moverResult{Mode: "Check", MissingOnSource: 4})
+			Expect(recorder.Events).To(Receive(And(
+				ContainSubstring(ReasonDriftDetected),
+				ContainSubstring("4 missing on source"),
+			)))
+		})
+
+		It("should not emit an event when the destination is in sync", func() {
+			r.recordCheckResult(context.Background(), dm, &moverResult{Mode: "Check"})
+			Expect(recorder.Events).NotTo(Receive())
+		})
+	})
+
+	Context("verification results", func() {
+		var (
+			r  *DataMoverReconciler
+			dm *datamoverv1alpha1.DataMover
+		)
+
+		BeforeEach(func() {
+			r = &DataMoverReconciler{Recorder: record.NewFakeRecorder(10)}
+			dm = &datamoverv1alpha1.DataMover{
+				ObjectMeta: metav1.ObjectMeta{Name: "verify", Namespace: "mover-verification", Generation: 3},
+			}
+		})
+
+		DescribeTable("should map the verification into the status and the Verified condition",
+			func(verification *moverVerification, status metav1.ConditionStatus, reason string) {
+				result := &moverResult{Mode: "Sync", Verification: verification}
+				verified := r.recordVerificationResult(context.Background(), dm, result)
+				Expect(verified).To(Equal(status == metav1.ConditionTrue))
+
+				Expect(dm.Status.Verification).To(Equal(result.verificationResult()))
+				condition := meta.FindStatusCondition(dm.Status.Conditions, ConditionVerified)
+				Expect(condition).NotTo(BeNil())
+				Expect(condition.Status).To(Equal(status))
+				Expect(condition.Reason).To(Equal(reason))
+				Expect(condition.ObservedGeneration).To(Equal(int64(3)))
+			},
+			Entry("passed", &moverVerification{Manifest: "s3:bucket/app/.datamover-manifest.sha256"},
+				metav1.ConditionTrue, ReasonVerificationPassed),
+			Entry("mismatches", &moverVerification{Mismatches: 2}, metav1.ConditionFalse, ReasonVerificationFailed),
+			Entry("missing", nil, metav1.ConditionFalse, ReasonVerificationMissing),
+		)
+
+		It("should count the mismatched files", func() {
+			mismatches := metrics.VerificationMismatchesTotal.WithLabelValues("mover-verification")
+			before := testutil.ToFloat64(mismatches)
+
+			r.recordVerificationResult(context.Background(), dm,
+				&moverResult{Mode: "Sync", Verification: &moverVerification{Mismatches: 3}})
+			Expect(testutil.ToFloat64(mismatches) - before).To(Equal(3.0))
+
+			r.recordVerificationResult(context.Background(), dm,
+				&moverResult{Mode: "Sync", Verification: &moverVerification{}})
+			Expect(testutil.ToFloat64(mismatches) - before).To(Equal(3.0))
+		})
+	})
+
+	DescribeTable("should map the dry run estimate into the status",
+		func(message string, expected *datamoverv1alpha1.TransferEstimate) {
+			result, err := parseMoverResult(message)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.transferEstimate()).To(Equal(expected))
+		},
+		Entry("estimate", `{"mode":"Sync","estimate":{"bytes":1048576,"files":12,"changes":3}}`,
+			&datamoverv1alpha1.TransferEstimate{Bytes: 1048576, Files: 12, Changes: 3}),
+		Entry("nothing to transfer", `{"mode":"Copy","estimate":{"bytes":0,"files":0,"changes":0}}`,
+			&datamoverv1alpha1.TransferEstimate{}),
+		Entry("no estimate", `{"mode":"Sync"}`, nil),
+	)
+})
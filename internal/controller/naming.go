@@ -0,0 +1,131 @@
+package controller
+
+import (
+	"context"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	datamoverv1alpha1 "a-cup-of.coffee/datamover-operator/api/v1alpha1"
+)
+
+// Labels set on every object created for a DataMover, used to find them again
+// when a reconcile was interrupted before the status was updated
+const (
+	LabelCreatedBy     = "app.kubernetes.io/created-by"
+	LabelDataMoverUID  = "datamover.a-cup-of.coffee/datamover-uid"
+	LabelComponent     = "datamover.a-cup-of.coffee/component"
+	createdByDataMover = "datamover-operator"
+)
+
+// Components of a DataMover run
+const (
+	ComponentClone    = "clone"
+	ComponentRestored = "restored"
+	ComponentSnapshot = "snapshot"
+	ComponentMover    = "mover"
+)
+
+// maxNameLength keeps child names usable as label values, such as the job-name label of mover pods
+const maxNameLength = 63
+
+// shortUIDLength is the number of UID characters appended to child names
+const shortUIDLength = 8
+
+// childName returns a deterministic name for an object owned by a DataMover.
+// The base is truncated so the name never exceeds maxNameLength.
+func childName(base, suffix string, uid types.UID) string {
+	tail := ""
+	if suffix != "" {
+		tail += "-" + suffix
+	}
+	if id := strings.ReplaceAll(string(uid), "-", ""); id != "" {
+		tail += "-" + id[:min(len(id), shortUIDLength)]
+	}
+
+	if len(base)+len(tail) > maxNameLength {
+		base = strings.TrimRight(base[:maxNameLength-len(tail)], "-.")
+	}
+	return base + tail
+}
+
+// cloneName returns the name of the PVC cloned from the source PVC
+func cloneName(dm *datamoverv1alpha1.DataMover) string {
+	return childName(dm.Spec.SourcePVC, "cloned", dm.UID)
+}
+
+// restoredName returns the name of the PVC restored from the source snapshot
+func restoredName(dm *datamoverv1alpha1.DataMover) string {
+	return childName(dm.Spec.SourcePVC, "restored", dm.UID)
+}
+
+// snapshotName returns the name of the snapshot used by the snapshot fallback
+func snapshotName(dm *datamoverv1alpha1.DataMover) string {
+	return childName(dm.Spec.SourcePVC, "snapshot", dm.UID)
+}
+
+// moverJobName returns the name of the rclone Job
+func moverJobName(dm *datamoverv1alpha1.DataMover) string {
+	return childName("verify-"+dm.Spec.SourcePVC, "", dm.UID)
+}
+
+// childLabels returns the labels identifying an object created for a DataMover
+func childLabels(dm *datamoverv1alpha1.DataMover, component string) map[string]string {
+	return map[string]string{
+		LabelCreatedBy:    createdByDataMover,
+		LabelDataMoverUID: string(dm.UID),
+		LabelComponent:    component,
+	}
+}
+
+// findChildPVC returns the PVC of the given component created for a DataMover, if any
+func (r *DataMoverReconciler) findChildPVC(
+	ctx context.Context,
+	dm *datamoverv1alpha1.DataMover,
+	component string,
+) (*corev1.PersistentVolumeClaim, error) {
+	var pvcs corev1.PersistentVolumeClaimList
+	if err := r.List(ctx, &pvcs, client.InNamespace(dm.Namespace), client.MatchingLabels{
+		LabelDataMoverUID: string(dm.UID),
+		LabelComponent:    component,
+	}); err != nil {
+		return nil, err
+	}
+	for i := range pvcs.Items {
+		if pvcs.Items[i].DeletionTimestamp.IsZero() {
+			return &pvcs.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// findMoverJob returns the rclone Job created for a DataMover, if any.
+// Jobs created before deterministic naming are looked up by their legacy name.
+func (r *DataMoverReconciler) findMoverJob(
+	ctx context.Context,
+	dm *datamoverv1alpha1.DataMover,
+) (*batchv1.Job, error) {
+	var jobs batchv1.JobList
+	if err := r.List(ctx, &jobs, client.InNamespace(dm.Namespace), client.MatchingLabels{
+		LabelDataMoverUID: string(dm.UID),
+		LabelComponent:    ComponentMover,
+	}); err != nil {
+		return nil, err
+	}
+	if len(jobs.Items) > 0 {
+		return &jobs.Items[0], nil
+	}
+
+	if dm.Status.RestoredPVCName == "" {
+		return nil, nil
+	}
+	var job batchv1.Job
+	legacyKey := types.NamespacedName{Name: "verify-" + dm.Status.RestoredPVCName, Namespace: dm.Namespace}
+	if err := r.Get(ctx, legacyKey, &job); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	return &job, nil
+}
@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	datamoverv1alpha1 "a-cup-of.coffee/datamover-operator/api/v1alpha1"
+)
+
+var _ = Describe("Child names", func() {
+	dm := &datamoverv1alpha1.DataMover{
+		ObjectMeta: metav1.ObjectMeta{UID: "6f1c3a8e-2b7d-4c1e-9a0b-3d5e7f9a1b2c"},
+		Spec:       datamoverv1alpha1.DataMoverSpec{SourcePVC: "web-data"},
+	}
+
+	It("should derive names from the DataMover UID", func() {
+		Expect(cloneName(dm)).To(Equal("web-data-cloned-6f1c3a8e"))
+		Expect(restoredName(dm)).To(Equal("web-data-restored-6f1c3a8e"))
+		Expect(snapshotName(dm)).To(Equal("web-data-snapshot-6f1c3a8e"))
+		Expect(moverJobName(dm)).To(Equal("verify-web-data-6f1c3a8e"))
+		Expect(cloneName(dm)).To(Equal(cloneName(dm.DeepCopy())))
+	})
+
+	It("should keep long names within the label value limit", func() {
+		long := dm.DeepCopy()
+		long.Spec.SourcePVC = strings.Repeat("a", 60) + "-data"
+
+		for _, name := range []string{cloneName(long), restoredName(long), snapshotName(long), moverJobName(long)} {
+			Expect(len(name)).To(BeNumerically("<=", maxNameLength))
+			Expect(name).To(HaveSuffix("-6f1c3a8e"))
+		}
+	})
+
+	It("should not leave a separator at the truncation point", func() {
+		Expect(childName(strings.Repeat("a", 46)+"-bbbbbbbbbbbbbbbbbbbb", "cloned", dm.UID)).
+			To(Equal(strings.Repeat("a", 46) + "-cloned-6f1c3a8e"))
+	})
+})
@@ -0,0 +1,128 @@
+package controller
+
+import (
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	datamoverv1alpha1 "a-cup-of.coffee/datamover-operator/api/v1alpha1"
+	"a-cup-of.coffee/datamover-operator/internal/metrics"
+)
+
+// setPhase moves the DataMover to a new phase. The timing of the current phase is closed
+// and the timing of the new phase is started.
+// Timings are stored in the status so durations survive controller restarts.
+func setPhase(dm *datamoverv1alpha1.DataMover, phase string) {
+	if dm.Status.Phase == phase {
+		return
+	}
+
+	now := metav1.Now()
+	if timing := findPhaseTiming(dm.Status.PhaseTimings, dm.Status.Phase); timing != nil && timing.EndTime == nil {
+		timing.EndTime = &now
+	}
+
+	if phase != PhaseInitial {
+		timing := datamoverv1alpha1.PhaseTiming{Phase: phase, StartTime: now}
+		if existing := findPhaseTiming(dm.Status.PhaseTimings, phase); existing != nil {
+			*existing = timing
+		} else {
+			dm.Status.PhaseTimings = append(dm.Status.PhaseTimings, timing)
+		}
+	}
+
+	dm.Status.Phase = phase
+}
+
+// phaseClock keeps the start time of the current phase of each DataMover to the nanosecond,
+// as the status stores times to the second. Durations fall back to the status times after a
+// controller restart.
+type phaseClock struct {
+	mu     sync.Mutex
+	starts map[types.NamespacedName]phaseStart
+}
+
+// phaseStart is the start time of a phase, as set in memory before the status is written
+type phaseStart struct {
+	phase string
+	time  time.Time
+}
+
+// phaseTransitions are the phase changes of a reconcile, recorded once the status was written
+type phaseTransitions struct {
+	key       types.NamespacedName
+	durations map[string]time.Duration
+	started   *phaseStart
+}
+
+// transitions returns the durations of the phases closed since the original status, and the
+// start of the phase entered, if any. It must run before the status is written, which drops
+// the fraction of a second of the times set by setPhase.
+func (c *phaseClock) transitions(original, dm *datamoverv1alpha1.DataMover) phaseTransitions {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := types.NamespacedName{Name: dm.Name, Namespace: dm.Namespace}
+	t := phaseTransitions{key: key, durations: map[string]time.Duration{}}
+	for _, timing := range dm.Status.PhaseTimings {
+		previous := findPhaseTiming(original.Status.PhaseTimings, timing.Phase)
+		if timing.EndTime != nil &&
+			(previous == nil || previous.EndTime == nil || !previous.EndTime.Equal(timing.EndTime)) {
+			start := timing.StartTime.Time
+			// The status only keeps the second the phase started at
+			if known, ok := c.starts[key]; ok && known.phase == timing.Phase &&
+				known.time.Truncate(time.Second).Equal(start.Truncate(time.Second)) {
+				start = known.time
+			}
+			t.durations[timing.Phase] = timing.EndTime.Sub(start)
+		}
+		if timing.Phase == dm.Status.Phase && timing.EndTime == nil &&
+			(previous == nil || !previous.StartTime.Equal(&timing.StartTime)) {
+			t.started = &phaseStart{phase: timing.Phase, time: timing.StartTime.Time}
+		}
+	}
+	return t
+}
+
+// record records the durations of the closed phases and remembers the start of the phase entered.
+// It runs once the status was written, so that a reconcile retried after a failed patch does
+// not record the same phase twice.
+func (c *phaseClock) record(t phaseTransitions) {
+	for phase, duration := range t.durations {
+		metrics.RecordPhaseDuration(phase, t.key.Namespace, duration.Seconds())
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if t.started == nil {
+		return
+	}
+	if isFinished(t.started.phase) {
+		// Terminal phases are never closed
+		delete(c.starts, t.key)
+		return
+	}
+	if c.starts == nil {
+		c.starts = map[types.NamespacedName]phaseStart{}
+	}
+	c.starts[t.key] = *t.started
+}
+
+// forget drops the start time kept for a deleted DataMover
+func (c *phaseClock) forget(key types.NamespacedName) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.starts, key)
+}
+
+// findPhaseTiming returns the timing of a phase, if it was started
+func findPhaseTiming(timings []datamoverv1alpha1.PhaseTiming, phase string) *datamoverv1alpha1.PhaseTiming {
+	for i := range timings {
+		if timings[i].Phase == phase {
+			return &timings[i]
+		}
+	}
+	return nil
+}
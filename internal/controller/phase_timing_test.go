@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	datamoverv1alpha1 "a-cup-of.coffee/datamover-operator/api/v1alpha1"
+	"a-cup-of.coffee/datamover-operator/internal/metrics"
+)
+
+var _ = Describe("Phase timings", func() {
+	It("should start and close phase timings on transitions", func() {
+		dm := &datamoverv1alpha1.DataMover{}
+
+		setPhase(dm, PhaseCreatingPVC)
+		Expect(dm.Status.Phase).To(Equal(PhaseCreatingPVC))
+		Expect(dm.Status.PhaseTimings).To(HaveLen(1))
+		Expect(dm.Status.PhaseTimings[0].EndTime).To(BeNil())
+
+		setPhase(dm, PhasePVCReady)
+		Expect(dm.Status.PhaseTimings).To(HaveLen(2))
+		creating := findPhaseTiming(dm.Status.PhaseTimings, PhaseCreatingPVC)
+		Expect(creating.EndTime).NotTo(BeNil())
+		Expect(findPhaseTiming(dm.Status.PhaseTimings, PhasePVCReady).EndTime).To(BeNil())
+	})
+
+	It("should keep the start time when the phase does not change", func() {
+		started := metav1.NewTime(time.Now().Add(-time.Hour))
+		dm := &datamoverv1alpha1.DataMover{
+			Status: datamoverv1alpha1.DataMoverStatus{
+				Phase:        PhaseCreatingPod,
+				PhaseTimings: []datamoverv1alpha1.PhaseTiming{{Phase: PhaseCreatingPod, StartTime: started}},
+			},
+		}
+
+		setPhase(dm, PhaseCreatingPod)
+		Expect(dm.Status.PhaseTimings[0].StartTime).To(Equal(started))
+		Expect(dm.Status.PhaseTimings[0].EndTime).To(BeNil())
+	})
+
+	Context("durations", func() {
+		histogram := func(phase, namespace string) *dto.Histogram {
+			var metric dto.Metric
+			observer := metrics.DataMoverPhaseDuration.WithLabelValues(phase, namespace)
+			Expect(observer.(prometheus.Metric).Write(&metric)).To(Succeed())
+			return metric.GetHistogram()
+		}
+
+		It("should record the duration of closed phases once the status is written", func() {
+			var clock phaseClock
+			dm := &datamoverv1alpha1.DataMover{ObjectMeta: metav1.ObjectMeta{Name: "once", Namespace: "phase-durations"}}
+			setPhase(dm, PhaseCreatingPVC)
+			original := dm.DeepCopy()
+
+			setPhase(dm, PhasePVCReady)
+			transitions := clock.transitions(original, dm)
+			Expect(histogram(PhaseCreatingPVC, "phase-durations").GetSampleCount()).To(BeZero())
+
+			clock.record(transitions)
+			Expect(histogram(PhaseCreatingPVC, "phase-durations").GetSampleCount()).To(Equal(uint64(1)))
+
+			// The next reconcile starts from the written status
+			clock.record(clock.transitions(dm.DeepCopy(), dm))
+			Expect(histogram(PhaseCreatingPVC, "phase-durations").GetSampleCount()).To(Equal(uint64(1)))
+		})
+
+		It("should keep the fraction of a second the status drops", func() {
+			var clock phaseClock
+			started := time.Date(2025, 6, 1, 12, 0, 0, 900_000_000, time.UTC)
+			dm := &datamoverv1alpha1.DataMover{ObjectMeta: metav1.ObjectMeta{Name: "short", Namespace: "phase-precision"}}
+			dm.Status.Phase = PhaseCreatingPVC
+			dm.Status.PhaseTimings = []datamoverv1alpha1.PhaseTiming{
+				{Phase: PhaseCreatingPVC, StartTime: metav1.NewTime(started)},
+			}
+			clock.record(clock.transitions(&datamoverv1alpha1.DataMover{}, dm))
+
+			// The status written to the API server only keeps the second
+			dm.Status.PhaseTimings[0].StartTime = metav1.NewTime(started.Truncate(time.Second))
+			original := dm.DeepCopy()
+			dm.Status.Phase = PhasePVCReady
+			ended := metav1.NewTime(started.Add(300 * time.Millisecond))
+			dm.Status.PhaseTimings[0].EndTime = &ended
+			clock.record(clock.transitions(original, dm))
+
+			observed := histogram(PhaseCreatingPVC, "phase-precision")
+			Expect(observed.GetSampleCount()).To(Equal(uint64(1)))
+			Expect(observed.GetSampleSum()).To(BeNumerically("~", 0.3, 0.001))
+		})
+	})
+})
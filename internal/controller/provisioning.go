@@ -0,0 +1,201 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	datamoverv1alpha1 "a-cup-of.coffee/datamover-operator/api/v1alpha1"
+	"a-cup-of.coffee/datamover-operator/internal/metrics"
+)
+
+// Reasons used when the working PVC cannot be provisioned
+const (
+	ReasonCloneNotSupported    = "CloneNotSupported"
+	ReasonQuotaExceeded        = "QuotaExceeded"
+	ReasonSizeMismatch         = "SizeMismatch"
+	ReasonInsufficientCapacity = "InsufficientCapacity"
+	ReasonProvisioningFailed   = "ProvisioningFailed"
+)
+
+// provisioningFailureThreshold is the number of unclassified provisioning failures
+// tolerated before giving up, as provisioners retry transient errors on their own
+const provisioningFailureThreshold = 5
+
+// provisioningFailurePatterns maps substrings of provisioner messages to a failure reason.
+// Patterns are matched in order against the lower-cased message.
+var provisioningFailurePatterns = []struct {
+	reason   string
+	patterns []string
+}{
+	{ReasonQuotaExceeded, []string{"exceeded quota", "quota exceeded", "quotaexceeded"}},
+	{ReasonSizeMismatch, []string{
+		"size mismatch", "smaller than", "larger than", "less than the size", "requested size",
+		"must be at least", "must be equal",
+	}},
+	{ReasonCloneNotSupported, []string{
+		"cloning is not supported", "clone is not supported", "cloning not supported", "clone not supported",
+		"does not support cloning", "does not support clone",
+		"cloning across storage classes", "same storage class for cloning",
+		"volume content source not supported", "volumecontentsource is not supported",
+	}},
+	{ReasonInsufficientCapacity, []string{
+		"insufficient", "no space", "out of space", "not enough space", "capacity", "resourceexhausted",
+	}},
+}
+
+// classifyProvisioningFailure returns the failure reason matching a provisioner message
+func classifyProvisioningFailure(message string) string {
+	message = strings.ToLower(message)
+	for _, class := range provisioningFailurePatterns {
+		for _, pattern := range class.patterns {
+			if strings.Contains(message, pattern) {
+				return class.reason
+			}
+		}
+	}
+	return ReasonProvisioningFailed
+}
+
+// isPermanentProvisioningFailure reports whether a provisioning failure will not resolve on retry
+func isPermanentProvisioningFailure(reason string) bool {
+	switch reason {
+	case ReasonCloneNotSupported, ReasonQuotaExceeded, ReasonSizeMismatch:
+		return true
+	default:
+		return false
+	}
+}
+
+// metricReason converts a condition reason into a metric label value
+func metricReason(reason string) string {
+	var b strings.Builder
+	for i, c := range reason {
+		if c >= 'A' && c <= 'Z' {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			c += 'a' - 'A'
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// handleProvisioningFailure inspects the provisioning events of the working PVC.
+// A cross-class clone refused by the CSI driver falls back to snapshot-then-restore,
+// other failures move the DataMover to the Failed phase with a precise reason once
+// they are permanent or repeated. It reports whether the reconcile was handled.
+func (r *DataMoverReconciler) handleProvisioningFailure(
+	ctx context.Context,
+	dm *datamoverv1alpha1.DataMover,
+	pvc *corev1.PersistentVolumeClaim,
+) (ctrl.Result, bool, error) {
+	logger := log.FromContext(ctx)
+
+	event, err := r.findProvisioningFailure(ctx, pvc)
+	if err != nil {
+		// Events are only a hint, keep waiting for the clone to bind
+		logger.Error(err, "Failed to list events of cloned PVC", "pvcName", pvc.Name)
+		metrics.RecordError("event_list_failed", PhaseCreatingPVC, dm.Namespace)
+		return ctrl.Result{}, false, nil
+	}
+	if event == nil {
+		return ctrl.Result{}, false, nil
+	}
+
+	reason := classifyProvisioningFailure(event.Message)
+	logger.Info("Cloned PVC provisioning failed", "pvcName", pvc.Name, "reason", reason, "message", event.Message)
+
+	if fallsBackToSnapshot(dm, reason) {
+		var sourcePVC corev1.PersistentVolumeClaim
+		if err := r.Get(ctx, types.NamespacedName{Name: dm.Spec.SourcePVC, Namespace: dm.Namespace}, &sourcePVC); err != nil {
+			logger.Error(err, "Failed to get source PVC")
+			metrics.RecordError("source_pvc_not_found", PhaseCreatingPVC, dm.Namespace)
+			return ctrl.Result{}, true, err
+		}
+		if isCrossClassClone(dm, &sourcePVC) {
+			result, err := r.fallbackToSnapshot(ctx, dm, pvc, event.Message)
+			return result, true, err
+		}
+	}
+
+	if !isPermanentProvisioningFailure(reason) && eventCount(event) < provisioningFailureThreshold {
+		return ctrl.Result{}, false, nil
+	}
+
+	r.afterStatusWrite(dm, func() {
+		metrics.RecordErrorWithReason("pvc_provisioning_failed", metricReason(reason), PhaseCreatingPVC, dm.Namespace)
+		metrics.RecordPVCCloneOperation("failure", dm.Namespace)
+	})
+	r.failDataMover(dm, reason,
+		fmt.Sprintf("PVC %s could not be provisioned: %s", pvc.Name, event.Message))
+	return ctrl.Result{}, true, nil
+}
+
+// fallsBackToSnapshot reports whether a provisioning failure may be worked around with the
+// snapshot fallback, which is only the case when the driver refused the clone. Other failures,
+// including unclassified ones that may be transient, never switch a run to the snapshot path.
+func fallsBackToSnapshot(dm *datamoverv1alpha1.DataMover, reason string) bool {
+	return dm.Status.SnapshotName == "" && reason == ReasonCloneNotSupported
+}
+
+// findProvisioningFailure returns the most recent ProvisioningFailed event of a PVC, if any
+func (r *DataMoverReconciler) findProvisioningFailure(
+	ctx context.Context,
+	pvc *corev1.PersistentVolumeClaim,
+) (*corev1.Event, error) {
+	return r.findLatestEvent(ctx, pvc.Namespace, pvc.UID, "ProvisioningFailed")
+}
+
+// findLatestEvent returns the most recent event with the given reason about an object, if any
+func (r *DataMoverReconciler) findLatestEvent(
+	ctx context.Context,
+	namespace string,
+	uid types.UID,
+	reason string,
+) (*corev1.Event, error) {
+	var events corev1.EventList
+	if err := r.uncachedReader().List(ctx, &events, client.InNamespace(namespace),
+		client.MatchingFields{
+			"involvedObject.uid": string(uid),
+			"reason":             reason,
+		}); err != nil {
+		return nil, err
+	}
+
+	var latest *corev1.Event
+	for i := range events.Items {
+		event := &events.Items[i]
+		if latest == nil || eventTime(event).After(eventTime(latest)) {
+			latest = event
+		}
+	}
+	return latest, nil
+}
+
+// eventTime returns the last time an event was observed
+func eventTime(event *corev1.Event) time.Time {
+	if !event.LastTimestamp.IsZero() {
+		return event.LastTimestamp.Time
+	}
+	if !event.EventTime.IsZero() {
+		return event.EventTime.Time
+	}
+	return event.CreationTimestamp.Time
+}
+
+// eventCount returns how many times an event was observed
+func eventCount(event *corev1.Event) int32 {
+	if event.Series != nil && event.Series.Count > event.Count {
+		return event.Series.Count
+	}
+	return max(event.Count, 1)
+}
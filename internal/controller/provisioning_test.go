@@ -0,0 +1,78 @@
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+
+	datamoverv1alpha1 "a-cup-of.coffee/datamover-operator/api/v1alpha1"
+)
+
+var _ = Describe("Provisioning failures", func() {
+	DescribeTable("should classify provisioner messages",
+		func(message, reason string) {
+			Expect(classifyProvisioningFailure(message)).To(Equal(reason))
+		},
+		Entry("unsupported clone",
+			`failed to provision volume with StorageClass "fast": rpc error: code = InvalidArgument `+
+				`desc = cloning across storage classes is not supported`,
+			ReasonCloneNotSupported),
+		Entry("quota",
+			`persistentvolumeclaims "data-cloned" is forbidden: exceeded quota: storage, `+
+				`requested: requests.storage=10Gi`,
+			ReasonQuotaExceeded),
+		Entry("size mismatch",
+			`failed to provision volume with StorageClass "fast": requested volume size 1Gi `+
+				`is smaller than the size of the source volume`,
+			ReasonSizeMismatch),
+		Entry("capacity",
+			`failed to provision volume with StorageClass "fast": rpc error: code = ResourceExhausted `+
+				`desc = insufficient capacity`,
+			ReasonInsufficientCapacity),
+		Entry("different storage class",
+			`failed to provision volume with StorageClass "fast": the source PVC and destination PVCs must be `+
+				`in the same storage class for cloning. Source is in "standard", but new PVC is in "fast"`,
+			ReasonCloneNotSupported),
+		Entry("missing clone capability",
+			`failed to provision volume with StorageClass "fast": rpc error: code = InvalidArgument `+
+				`desc = driver does not support CLONE_VOLUME`,
+			ReasonCloneNotSupported),
+		Entry("unknown", `failed to provision volume with StorageClass "fast": timeout`, ReasonProvisioningFailed),
+		Entry("missing data source",
+			`failed to provision volume with StorageClass "fast": error getting handle for DataSource Type `+
+				`PersistentVolumeClaim by Name data: error getting PVC data from api server: timed out`,
+			ReasonProvisioningFailed),
+		Entry("transient clone error",
+			`failed to provision volume with StorageClass "fast": rpc error: code = DeadlineExceeded `+
+				`desc = context deadline exceeded while cloning volume`,
+			ReasonProvisioningFailed),
+	)
+
+	It("should only fall back to a snapshot when the driver refused the clone", func() {
+		dm := &datamoverv1alpha1.DataMover{}
+		Expect(fallsBackToSnapshot(dm, ReasonCloneNotSupported)).To(BeTrue())
+		Expect(fallsBackToSnapshot(dm, ReasonProvisioningFailed)).To(BeFalse())
+		Expect(fallsBackToSnapshot(dm, ReasonInsufficientCapacity)).To(BeFalse())
+
+		dm.Status.SnapshotName = "data-snapshot-1234abcd"
+		Expect(fallsBackToSnapshot(dm, ReasonCloneNotSupported)).To(BeFalse())
+	})
+
+	It("should only treat configuration errors as permanent", func() {
+		Expect(isPermanentProvisioningFailure(ReasonCloneNotSupported)).To(BeTrue())
+		Expect(isPermanentProvisioningFailure(ReasonQuotaExceeded)).To(BeTrue())
+		Expect(isPermanentProvisioningFailure(ReasonInsufficientCapacity)).To(BeFalse())
+		Expect(isPermanentProvisioningFailure(ReasonProvisioningFailed)).To(BeFalse())
+	})
+
+	It("should convert reasons into metric labels", func() {
+		Expect(metricReason(ReasonCloneNotSupported)).To(Equal("clone_not_supported"))
+		Expect(metricReason(ReasonQuotaExceeded)).To(Equal("quota_exceeded"))
+	})
+
+	It("should count event series", func() {
+		Expect(eventCount(&corev1.Event{})).To(Equal(int32(1)))
+		Expect(eventCount(&corev1.Event{Count: 3})).To(Equal(int32(3)))
+		Expect(eventCount(&corev1.Event{Series: &corev1.EventSeries{Count: 7}})).To(Equal(int32(7)))
+	})
+})
@@ -0,0 +1,93 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// RetainedCloneReconciler enforces the retention policy of retained clones.
+// Clones outlive the DataMovers that created them, so their expiry cannot rely on
+// a DataMover of the same source being reconciled.
+type RetainedCloneReconciler struct {
+	client.Client
+	Log      logr.Logger
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;delete
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Reconcile deletes the expired clones of the source PVC of a retained clone,
+// and requeues until the next clone of that source expires.
+func (r *RetainedCloneReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var pvc corev1.PersistentVolumeClaim
+	if err := r.Get(ctx, req.NamespacedName, &pvc); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	source := pvc.Labels[LabelSourcePVC]
+	if pvc.Labels[LabelRetained] != "true" || source == "" {
+		return ctrl.Result{}, nil
+	}
+
+	now := time.Now()
+	kept, deleted, err := sweepClones(ctx, r.Client, pvc.Namespace, source, now)
+	for _, clone := range deleted {
+		r.Recorder.Eventf(clone.pvc, corev1.EventTypeNormal, EventCloneExpired,
+			"Deleted cloned PVC %s, retained since %s", clone.pvc.Name, clone.retainedAt.Format(time.RFC3339))
+	}
+	if err != nil {
+		logger.Error(err, "Failed to sweep retained clones", "sourcePVC", source)
+		return ctrl.Result{}, err
+	}
+
+	nextExpiry := nextCloneExpiry(kept)
+	if nextExpiry == nil {
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{RequeueAfter: nextExpiry.Sub(now) + time.Second}, nil
+}
+
+// isRetainedClone reports whether an object is a clone kept by the retention policy
+func isRetainedClone(obj client.Object) bool {
+	return obj.GetLabels()[LabelRetained] == "true"
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RetainedCloneReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("retainedclone-controller")
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("retainedclone").
+		// Only clones marked as retained are swept, other PVCs are left to the DataMover controller
+		For(&corev1.PersistentVolumeClaim{}, builder.WithPredicates(predicate.NewPredicateFuncs(isRetainedClone))).
+		Complete(r)
+}
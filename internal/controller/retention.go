@@ -0,0 +1,239 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	datamoverv1alpha1 "a-cup-of.coffee/datamover-operator/api/v1alpha1"
+	"a-cup-of.coffee/datamover-operator/internal/metrics"
+)
+
+// Labels and annotations set on clones kept by the retention policy.
+// The policy is copied on the clone so it is still enforced once its DataMover is gone.
+const (
+	LabelRetained         = "datamover.a-cup-of.coffee/retained"
+	LabelSourcePVC        = "datamover.a-cup-of.coffee/source-pvc"
+	AnnotationRetainedAt  = "datamover.a-cup-of.coffee/retained-at"
+	AnnotationRetainFor   = "datamover.a-cup-of.coffee/retain-for"
+	AnnotationRetainCount = "datamover.a-cup-of.coffee/retain-count"
+)
+
+const (
+	EventCloneRetained = "CloneRetained"
+	EventCloneExpired  = "CloneExpired"
+)
+
+// retentionEnabled reports whether the clone is kept after the backup under a retention policy
+func retentionEnabled(dm *datamoverv1alpha1.DataMover) bool {
+	return !dm.Spec.DeletePvcAfterBackup && dm.Spec.Clone != nil &&
+		(dm.Spec.Clone.RetainFor != nil || dm.Spec.Clone.RetainCount != nil)
+}
+
+// sourceLabelValue returns the value of the source label for a PVC name.
+// Names longer than a label value are truncated and suffixed with a hash of the full name.
+func sourceLabelValue(name string) string {
+	if len(name) <= maxNameLength {
+		return name
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(name))
+	suffix := fmt.Sprintf("-%08x", h.Sum32())
+	return strings.TrimRight(name[:maxNameLength-len(suffix)], "-.") + suffix
+}
+
+// retainClone marks the clone of a completed DataMover as retained, copying the retention policy
+func (r *DataMoverReconciler) retainClone(
+	ctx context.Context,
+	dm *datamoverv1alpha1.DataMover,
+) error {
+	if dm.Status.RestoredPVCName == "" {
+		return nil
+	}
+
+	var pvc corev1.PersistentVolumeClaim
+	key := types.NamespacedName{Name: dm.Status.RestoredPVCName, Namespace: dm.Namespace}
+	if err := r.Get(ctx, key, &pvc); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	patch := client.MergeFrom(pvc.DeepCopy())
+	if pvc.Labels == nil {
+		pvc.Labels = map[string]string{}
+	}
+	if pvc.Annotations == nil {
+		pvc.Annotations = map[string]string{}
+	}
+	pvc.Labels[LabelRetained] = "true"
+	pvc.Labels[LabelSourcePVC] = sourceLabelValue(dm.Spec.SourcePVC)
+	pvc.Annotations[AnnotationRetainedAt] = time.Now().UTC().Format(time.RFC3339)
+	if retainFor := dm.Spec.Clone.RetainFor; retainFor != nil {
+		pvc.Annotations[AnnotationRetainFor] = retainFor.Duration.String()
+	}
+	if retainCount := dm.Spec.Clone.RetainCount; retainCount != nil {
+		pvc.Annotations[AnnotationRetainCount] = strconv.Itoa(int(*retainCount))
+	}
+	if err := r.Patch(ctx, &pvc, patch); err != nil {
+		return err
+	}
+
+	log.FromContext(ctx).Info("Retained cloned PVC", "pvcName", pvc.Name)
+	r.Recorder.Eventf(dm, corev1.EventTypeNormal, EventCloneRetained, "Kept cloned PVC %s as a restore point", pvc.Name)
+	return nil
+}
+
+// retainedClone is a clone with the retention policy read from its annotations
+type retainedClone struct {
+	pvc        *corev1.PersistentVolumeClaim
+	retainedAt time.Time
+	expiresAt  *time.Time
+}
+
+// parseRetainedClone reads the retention annotations of a clone.
+// Clones with an invalid retained-at annotation are never expired.
+func parseRetainedClone(pvc *corev1.PersistentVolumeClaim) (retainedClone, bool) {
+	retainedAt, err := time.Parse(time.RFC3339, pvc.Annotations[AnnotationRetainedAt])
+	if err != nil {
+		return retainedClone{}, false
+	}
+
+	clone := retainedClone{pvc: pvc, retainedAt: retainedAt}
+	if retainFor, err := time.ParseDuration(pvc.Annotations[AnnotationRetainFor]); err == nil {
+		expiresAt := retainedAt.Add(retainFor)
+		clone.expiresAt = &expiresAt
+	}
+	return clone, true
+}
+
+// selectExpiredClones splits the retained clones of a source into the ones to keep and the
+// ones to delete, newest first. A clone expires once its retainFor elapsed, or when it falls
+// beyond the retainCount of the newest clone.
+func selectExpiredClones(pvcs []corev1.PersistentVolumeClaim, now time.Time) (kept, expired []retainedClone) {
+	clones := make([]retainedClone, 0, len(pvcs))
+	for i := range pvcs {
+		if !pvcs[i].DeletionTimestamp.IsZero() {
+			continue
+		}
+		if clone, ok := parseRetainedClone(&pvcs[i]); ok {
+			clones = append(clones, clone)
+		}
+	}
+	sort.Slice(clones, func(i, j int) bool {
+		if clones[i].retainedAt.Equal(clones[j].retainedAt) {
+			return clones[i].pvc.Name > clones[j].pvc.Name
+		}
+		return clones[i].retainedAt.After(clones[j].retainedAt)
+	})
+
+	retainCount := -1
+	if len(clones) > 0 {
+		if count, err := strconv.Atoi(clones[0].pvc.Annotations[AnnotationRetainCount]); err == nil {
+			retainCount = count
+		}
+	}
+
+	for i, clone := range clones {
+		if (retainCount >= 0 && i >= retainCount) || (clone.expiresAt != nil && !now.Before(*clone.expiresAt)) {
+			expired = append(expired, clone)
+		} else {
+			kept = append(kept, clone)
+		}
+	}
+	return kept, expired
+}
+
+// sweepClones deletes the expired retained clones of a source PVC, named by its source label value,
+// and returns the kept clones, newest first, and the deleted ones
+func sweepClones(
+	ctx context.Context,
+	c client.Client,
+	namespace, source string,
+	now time.Time,
+) (kept, deleted []retainedClone, err error) {
+	logger := log.FromContext(ctx)
+
+	var pvcs corev1.PersistentVolumeClaimList
+	if err := c.List(ctx, &pvcs, client.InNamespace(namespace), client.MatchingLabels{
+		LabelRetained:  "true",
+		LabelSourcePVC: source,
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	kept, expired := selectExpiredClones(pvcs.Items, now)
+	for _, clone := range expired {
+		logger.Info("Deleting expired cloned PVC", "pvcName", clone.pvc.Name)
+		if err := c.Delete(ctx, clone.pvc); err != nil && !errors.IsNotFound(err) {
+			logger.Error(err, "Failed to delete expired cloned PVC", "pvcName", clone.pvc.Name)
+			metrics.RecordError("pvc_delete_failed", PhaseCompleted, namespace)
+			metrics.RecordPVCCleanupOperation("failure", namespace)
+			return kept, deleted, err
+		}
+		metrics.RecordPVCCleanupOperation("expired", namespace)
+		deleted = append(deleted, clone)
+	}
+	return kept, deleted, nil
+}
+
+// nextCloneExpiry returns when the first of the kept clones expires, if any does
+func nextCloneExpiry(kept []retainedClone) *time.Time {
+	var next *time.Time
+	for _, clone := range kept {
+		if clone.expiresAt != nil && (next == nil || clone.expiresAt.Before(*next)) {
+			next = clone.expiresAt
+		}
+	}
+	return next
+}
+
+// sweepRetainedClones deletes the expired clones of the source PVC and reports the kept ones.
+// The DataMover requeues until the next expiry as long as its own clone is kept.
+func (r *DataMoverReconciler) sweepRetainedClones(
+	ctx context.Context,
+	dm *datamoverv1alpha1.DataMover,
+) (ctrl.Result, error) {
+	now := time.Now()
+	kept, deleted, err := sweepClones(ctx, r.Client, dm.Namespace, sourceLabelValue(dm.Spec.SourcePVC), now)
+	for _, clone := range deleted {
+		r.Recorder.Eventf(dm, corev1.EventTypeNormal, EventCloneExpired,
+			"Deleted cloned PVC %s, retained since %s", clone.pvc.Name, clone.retainedAt.Format(time.RFC3339))
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	dm.Status.RetainedClones = nil
+	ownCloneKept := false
+	for _, clone := range kept {
+		status := datamoverv1alpha1.RetainedClone{
+			Name:       clone.pvc.Name,
+			RetainedAt: metav1.NewTime(clone.retainedAt),
+		}
+		if clone.expiresAt != nil {
+			expiresAt := metav1.NewTime(*clone.expiresAt)
+			status.ExpiresAt = &expiresAt
+		}
+		dm.Status.RetainedClones = append(dm.Status.RetainedClones, status)
+		ownCloneKept = ownCloneKept || clone.pvc.Name == dm.Status.RestoredPVCName
+	}
+
+	// Newer DataMovers of the same source take over once this clone is gone,
+	// and the retained clone controller once no DataMover is left
+	nextExpiry := nextCloneExpiry(kept)
+	if !ownCloneKept || nextExpiry == nil {
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{RequeueAfter: nextExpiry.Sub(now) + time.Second}, nil
+}
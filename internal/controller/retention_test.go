@@ -0,0 +1,140 @@
+package controller
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	datamoverv1alpha1 "a-cup-of.coffee/datamover-operator/api/v1alpha1"
+)
+
+var _ = Describe("Clone retention", func() {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	retained := func(name string, age time.Duration, annotations map[string]string) corev1.PersistentVolumeClaim {
+		pvc := corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				Annotations: map[string]string{
+					AnnotationRetainedAt: now.Add(-age).Format(time.RFC3339),
+				},
+			},
+		}
+		for key, value := range annotations {
+			pvc.Annotations[key] = value
+		}
+		return pvc
+	}
+
+	names := func(clones []retainedClone) []string {
+		result := []string{}
+		for _, clone := range clones {
+			result = append(result, clone.pvc.Name)
+		}
+		return result
+	}
+
+	It("should only retain clones kept after the backup", func() {
+		dm := &datamoverv1alpha1.DataMover{}
+		Expect(retentionEnabled(dm)).To(BeFalse())
+
+		dm.Spec.Clone = &datamoverv1alpha1.CloneSpec{RetainCount: &[]int32{2}[0]}
+		Expect(retentionEnabled(dm)).To(BeTrue())
+
+		dm.Spec.DeletePvcAfterBackup = true
+		Expect(retentionEnabled(dm)).To(BeFalse())
+	})
+
+	It("should keep the newest clones up to the retain count", func() {
+		count := map[string]string{AnnotationRetainCount: "2"}
+		kept, expired := selectExpiredClones([]corev1.PersistentVolumeClaim{
+			retained("oldest", 3*time.Hour, count),
+			retained("newest", time.Hour, count),
+			retained("middle", 2*time.Hour, count),
+		}, now)
+
+		Expect(names(kept)).To(Equal([]string{"newest", "middle"}))
+		Expect(names(expired)).To(Equal([]string{"oldest"}))
+	})
+
+	It("should expire clones retained for longer than retainFor", func() {
+		retainFor := map[string]string{AnnotationRetainFor: "24h0m0s"}
+		kept, expired := selectExpiredClones([]corev1.PersistentVolumeClaim{
+			retained("recent", time.Hour, retainFor),
+			retained("old", 25*time.Hour, retainFor),
+		}, now)
+
+		Expect(names(kept)).To(Equal([]string{"recent"}))
+		Expect(*kept[0].expiresAt).To(Equal(now.Add(23 * time.Hour)))
+		Expect(names(expired)).To(Equal([]string{"old"}))
+	})
+
+	It("should never expire clones without a valid retention timestamp", func() {
+		pvc := retained("invalid", 0, nil)
+		pvc.Annotations[AnnotationRetainedAt] = "yesterday"
+		kept, expired := selectExpiredClones([]corev1.PersistentVolumeClaim{pvc}, now)
+		Expect(kept).To(BeEmpty())
+		Expect(expired).To(BeEmpty())
+	})
+
+	It("should fit long source names in a label value", func() {
+		Expect(sourceLabelValue("app-data")).To(Equal("app-data"))
+
+		long := strings.Repeat("a", 80)
+		value := sourceLabelValue(long)
+		Expect(len(value)).To(BeNumerically("<=", maxNameLength))
+		Expect(value).To(Equal(sourceLabelValue(long)))
+		Expect(value).NotTo(Equal(sourceLabelValue(strings.Repeat("a", 81))))
+	})
+
+	It("should expire clones once no DataMover of their source is left", func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+
+		clone := func(name string, age time.Duration) *corev1.PersistentVolumeClaim {
+			return &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: "default",
+					Labels:    map[string]string{LabelRetained: "true", LabelSourcePVC: "app-data"},
+					Annotations: map[string]string{
+						AnnotationRetainedAt: time.Now().Add(-age).UTC().Format(time.RFC3339),
+						AnnotationRetainFor:  "24h0m0s",
+					},
+				},
+			}
+		}
+		recorder := record.NewFakeRecorder(10)
+		r := &RetainedCloneReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(clone("app-data-old", 25*time.Hour), clone("app-data-recent", time.Hour)).Build(),
+			Recorder: recorder,
+		}
+
+		result, err := r.Reconcile(context.Background(), ctrl.Request{
+			NamespacedName: types.NamespacedName{Name: "app-data-recent", Namespace: "default"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeNumerically("~", 23*time.Hour, time.Minute))
+		Expect(recorder.Events).To(Receive(ContainSubstring(EventCloneExpired)))
+
+		var pvc corev1.PersistentVolumeClaim
+		err = r.Get(context.Background(), client.ObjectKey{Name: "app-data-old", Namespace: "default"}, &pvc)
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+		Expect(r.Get(context.Background(), client.ObjectKey{Name: "app-data-recent", Namespace: "default"}, &pvc)).
+			To(Succeed())
+	})
+})
@@ -0,0 +1,125 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	// maxScheduleIterations bounds the search for the next run, which only loops over the
+	// wall-clock times that do not exist or are repeated around DST transitions
+	maxScheduleIterations = 1000
+
+	// maxCatchUpWindow bounds how far back runs missed while the operator was unavailable are looked for
+	maxCatchUpWindow = 24 * time.Hour
+	// maxMissedRuns bounds the number of runs due at once, schedules missing more wait for the next run
+	maxMissedRuns = 100
+)
+
+// cronSchedule evaluates a cron expression on the wall clock of a time zone.
+// Runs falling in a skipped hour (spring forward) are shifted after the gap, and runs in a
+// repeated hour (fall back) happen once.
+type cronSchedule struct {
+	schedule cron.Schedule
+	location *time.Location
+}
+
+// parseSchedule parses a standard cron expression, including macros such as @daily or
+// @every 6h. The time zone is taken from timeZone, or from a CRON_TZ= or TZ= prefix,
+// and defaults to the local time zone of the operator. H values are hashed from the seed.
+func parseSchedule(spec string, timeZone *string, seed string) (*cronSchedule, error) {
+	spec = strings.TrimSpace(spec)
+	location := time.Local
+
+	var prefixZone string
+	if strings.HasPrefix(spec, "CRON_TZ=") || strings.HasPrefix(spec, "TZ=") {
+		zone, rest, found := strings.Cut(spec, " ")
+		if !found {
+			return nil, fmt.Errorf("missing cron expression after %q", zone)
+		}
+		_, prefixZone, _ = strings.Cut(zone, "=")
+		spec = strings.TrimSpace(rest)
+	}
+
+	switch {
+	case prefixZone != "" && timeZone != nil:
+		return nil, fmt.Errorf("the time zone must be set in timeZone, not in the schedule")
+	case prefixZone != "":
+		timeZone = &prefixZone
+	}
+	if timeZone != nil {
+		loaded, err := time.LoadLocation(*timeZone)
+		if err != nil {
+			return nil, fmt.Errorf("unknown time zone %q: %w", *timeZone, err)
+		}
+		location = loaded
+	}
+
+	spec, err := expandHashes(spec, seed)
+	if err != nil {
+		return nil, err
+	}
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		return nil, err
+	}
+	// Expressions are matched against wall-clock times represented in UTC, which has no DST
+	if specSchedule, ok := schedule.(*cron.SpecSchedule); ok {
+		specSchedule.Location = time.UTC
+	}
+	return &cronSchedule{schedule: schedule, location: location}, nil
+}
+
+// Next returns the first run strictly after the given time, or the zero time if there is none
+func (s *cronSchedule) Next(after time.Time) time.Time {
+	// Fixed intervals do not depend on the wall clock
+	if _, ok := s.schedule.(cron.ConstantDelaySchedule); ok {
+		return s.schedule.Next(after)
+	}
+
+	wall := toWallClock(after, s.location)
+	for range maxScheduleIterations {
+		wall = s.schedule.Next(wall)
+		if wall.IsZero() {
+			return time.Time{}
+		}
+		// The wall clock repeats itself when the clocks fall back
+		if next := fromWallClock(wall, s.location); next.After(after) {
+			return next
+		}
+	}
+	return time.Time{}
+}
+
+// toWallClock returns the wall-clock time of t in a location, represented in UTC
+func toWallClock(t time.Time, location *time.Location) time.Time {
+	local := t.In(location)
+	return time.Date(local.Year(), local.Month(), local.Day(),
+		local.Hour(), local.Minute(), local.Second(), local.Nanosecond(), time.UTC)
+}
+
+// fromWallClock returns the time at which the wall clock of a location shows the given time.
+// Wall-clock times skipped by a DST transition are shifted forward by the length of the gap.
+func fromWallClock(wall time.Time, location *time.Location) time.Time {
+	return time.Date(wall.Year(), wall.Month(), wall.Day(),
+		wall.Hour(), wall.Minute(), wall.Second(), wall.Nanosecond(), location)
+}
+
+// dueRuns returns the run times after earliest and up to now, oldest first.
+// Only the last maxCatchUpWindow is searched, and tooMany is set instead when more than
+// maxMissedRuns runs are due.
+func (s *cronSchedule) dueRuns(earliest, now time.Time) (runs []time.Time, tooMany bool) {
+	if window := now.Add(-maxCatchUpWindow); earliest.Before(window) {
+		earliest = window
+	}
+	for next := s.Next(earliest); !next.IsZero() && !next.After(now); next = s.Next(next) {
+		if len(runs) == maxMissedRuns {
+			return nil, true
+		}
+		runs = append(runs, next)
+	}
+	return runs, false
+}
@@ -0,0 +1,180 @@
+package controller
+
+import (
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	datamoverv1alpha1 "a-cup-of.coffee/datamover-operator/api/v1alpha1"
+)
+
+const (
+	// ConditionHealthy reports whether the last finished run of a schedule succeeded
+	ConditionHealthy = "Healthy"
+
+	ReasonLastRunSucceeded = "LastRunSucceeded"
+	ReasonLastRunFailed    = "LastRunFailed"
+	ReasonNoRunFinished    = "NoRunFinished"
+
+	// ReasonScheduling is the reason of the Suspended condition of a schedule that is not suspended
+	ReasonScheduling = "Scheduling"
+
+	// lastRunPhasePending is reported for a run whose DataMover did not start yet
+	lastRunPhasePending = "Pending"
+)
+
+// scheduleRuns groups the DataMovers created by a schedule by outcome, oldest first
+type scheduleRuns struct {
+	active     []*datamoverv1alpha1.DataMover
+	successful []*datamoverv1alpha1.DataMover
+	failed     []*datamoverv1alpha1.DataMover
+	cancelled  []*datamoverv1alpha1.DataMover
+}
+
+// groupRuns sorts the DataMovers of a schedule by creation time and groups them by outcome
+func groupRuns(items []datamoverv1alpha1.DataMover) scheduleRuns {
+	sorted := make([]*datamoverv1alpha1.DataMover, 0, len(items))
+	for i := range items {
+		sorted = append(sorted, &items[i])
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].CreationTimestamp.Equal(&sorted[j].CreationTimestamp) {
+			return sorted[i].Name < sorted[j].Name
+		}
+		return sorted[i].CreationTimestamp.Before(&sorted[j].CreationTimestamp)
+	})
+
+	var runs scheduleRuns
+	for _, dm := range sorted {
+		switch dm.Status.Phase {
+		case PhaseCompleted:
+			runs.successful = append(runs.successful, dm)
+		case PhaseFailed:
+			runs.failed = append(runs.failed, dm)
+		case PhaseCancelled:
+			// Cancelled runs are finished but not counted as failures
+			runs.cancelled = append(runs.cancelled, dm)
+		default:
+			runs.active = append(runs.active, dm)
+		}
+	}
+	return runs
+}
+
+// latest returns the most recently created of the given runs
+func latest(groups ...[]*datamoverv1alpha1.DataMover) *datamoverv1alpha1.DataMover {
+	var last *datamoverv1alpha1.DataMover
+	for _, group := range groups {
+		for _, dm := range group {
+			if last == nil || last.CreationTimestamp.Before(&dm.CreationTimestamp) ||
+				(last.CreationTimestamp.Equal(&dm.CreationTimestamp) && last.Name < dm.Name) {
+				last = dm
+			}
+		}
+	}
+	return last
+}
+
+// latestFinish returns when the last of the given runs finished, keeping the previous value
+// when it is more recent, as older runs may have been pruned
+func latestFinish(previous *metav1.Time, runs []*datamoverv1alpha1.DataMover) *metav1.Time {
+	last := previous
+	for _, dm := range runs {
+		if finished := finishedAt(dm); last == nil || finished.After(last.Time) {
+			last = &metav1.Time{Time: finished}
+		}
+	}
+	return last
+}
+
+// runReference returns the reference of a run listed in the status of its schedule
+func runReference(dm *datamoverv1alpha1.DataMover) corev1.ObjectReference {
+	return corev1.ObjectReference{
+		Kind:      "DataMover",
+		Namespace: dm.Namespace,
+		Name:      dm.Name,
+		UID:       dm.UID,
+	}
+}
+
+// setRunStatus recomputes the status of a schedule from its current runs.
+// The successful and failed counters are left to the caller, which prunes the finished runs.
+func setRunStatus(schedule *datamoverv1alpha1.DataMoverSchedule, runs scheduleRuns) {
+	status := &schedule.Status
+
+	status.Active = nil
+	for _, dm := range runs.active {
+		status.Active = append(status.Active, runReference(dm))
+	}
+	status.ActiveJobs = int32(len(runs.active))
+	countFailures(schedule, runs)
+	status.LastSuccessfulTime = latestFinish(status.LastSuccessfulTime, runs.successful)
+	// Failures awaiting a retry are left out, so they are still counted if the retry never starts
+	status.LastFailureTime = latestFinish(status.LastFailureTime, settledFailures(schedule, runs))
+
+	status.LastRunPhase = ""
+	if last := latest(runs.active, runs.successful, runs.failed, runs.cancelled); last != nil {
+		status.LastRunPhase = last.Status.Phase
+		if status.LastRunPhase == PhaseInitial {
+			status.LastRunPhase = lastRunPhasePending
+		}
+	}
+
+	setHealthyCondition(schedule, latest(runs.successful, runs.failed))
+}
+
+// setHealthyCondition reports the outcome of the last finished run, ignoring cancelled runs
+func setHealthyCondition(schedule *datamoverv1alpha1.DataMoverSchedule, last *datamoverv1alpha1.DataMover) {
+	condition := metav1.Condition{
+		Type:               ConditionHealthy,
+		Status:             metav1.ConditionUnknown,
+		Reason:             ReasonNoRunFinished,
+		Message:            "No run finished yet",
+		ObservedGeneration: schedule.Generation,
+	}
+	switch {
+	case last == nil:
+		if meta.FindStatusCondition(schedule.Status.Conditions, ConditionHealthy) != nil {
+			// The finished runs were pruned, the last outcome is still the latest known
+			return
+		}
+	case last.Status.Phase == PhaseCompleted:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = ReasonLastRunSucceeded
+		condition.Message = fmt.Sprintf("DataMover %s completed", last.Name)
+	default:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = ReasonLastRunFailed
+		condition.Message = fmt.Sprintf("DataMover %s failed", last.Name)
+		if last.Status.LastError != nil {
+			condition.Message = fmt.Sprintf("DataMover %s failed (%s): %s",
+				last.Name, last.Status.LastError.Reason, last.Status.LastError.Message)
+		}
+	}
+	meta.SetStatusCondition(&schedule.Status.Conditions, condition)
+}
+
+// setSuspendedCondition keeps the Suspended condition in sync with spec.suspend
+func setSuspendedCondition(schedule *datamoverv1alpha1.DataMoverSchedule) {
+	if schedule.Spec.Suspend {
+		reason, message := suspendedReason(schedule)
+		meta.SetStatusCondition(&schedule.Status.Conditions, metav1.Condition{
+			Type:               ConditionSuspended,
+			Status:             metav1.ConditionTrue,
+			Reason:             reason,
+			Message:            message,
+			ObservedGeneration: schedule.Generation,
+		})
+		return
+	}
+	meta.SetStatusCondition(&schedule.Status.Conditions, metav1.Condition{
+		Type:               ConditionSuspended,
+		Status:             metav1.ConditionFalse,
+		Reason:             ReasonScheduling,
+		Message:            "Runs are scheduled",
+		ObservedGeneration: schedule.Generation,
+	})
+}
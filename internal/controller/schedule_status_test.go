@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	datamoverv1alpha1 "a-cup-of.coffee/datamover-operator/api/v1alpha1"
+)
+
+var _ = Describe("Schedule status", func() {
+	base := time.Date(2025, 6, 1, 2, 0, 0, 0, time.UTC)
+
+	run := func(name string, created time.Duration, phase string) datamoverv1alpha1.DataMover {
+		return datamoverv1alpha1.DataMover{
+			ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(base.Add(created))},
+			Status:     datamoverv1alpha1.DataMoverStatus{Phase: phase},
+		}
+	}
+
+	It("should group runs by outcome, oldest first", func() {
+		runs := groupRuns([]datamoverv1alpha1.DataMover{
+			run("third", 2*time.Hour, PhaseCompleted),
+			run("first", 0, PhaseCompleted),
+			run("second", time.Hour, PhaseFailed),
+			run("fourth", 3*time.Hour, PhaseCancelled),
+			run("fifth", 4*time.Hour, PhaseCreatingPod),
+		})
+
+		Expect(runs.successful).To(HaveLen(2))
+		Expect(runs.successful[0].Name).To(Equal("first"))
+		Expect(runs.failed).To(HaveLen(1))
+		Expect(runs.cancelled).To(HaveLen(1))
+		Expect(runs.active).To(HaveLen(1))
+	})
+
+	It("should only list unfinished runs as active", func() {
+		schedule := &datamoverv1alpha1.DataMoverSchedule{}
+		schedule.Status.Active = []corev1.ObjectReference{{Name: "finished-long-ago"}}
+
+		setRunStatus(schedule, groupRuns([]datamoverv1alpha1.DataMover{
+			run("finished-long-ago", 0, PhaseCompleted),
+			run("uploading", time.Hour, PhaseCreatingPod),
+			run("pending", 2*time.Hour, PhaseInitial),
+		}))
+
+		Expect(schedule.Status.ActiveJobs).To(Equal(int32(2)))
+		Expect(schedule.Status.Active[0].Name).To(Equal("uploading"))
+		Expect(schedule.Status.LastRunPhase).To(Equal("Pending"))
+		Expect(schedule.Status.LastSuccessfulTime.Time).To(Equal(base))
+	})
+
+	It("should report the outcome of the last finished run", func() {
+		schedule := &datamoverv1alpha1.DataMoverSchedule{}
+		setRunStatus(schedule, groupRuns(nil))
+		Expect(meta.FindStatusCondition(schedule.Status.Conditions, ConditionHealthy).Reason).
+			To(Equal(ReasonNoRunFinished))
+
+		failed := run("failed", time.Hour, PhaseFailed)
+		failed.Status.LastError = &datamoverv1alpha1.MoverError{Reason: "AuthenticationFailed", Message: "403 Forbidden"}
+		setRunStatus(schedule, groupRuns([]datamoverv1alpha1.DataMover{
+			run("succeeded", 0, PhaseCompleted),
+			failed,
+			run("cancelled", 2*time.Hour, PhaseCancelled),
+		}))
+
+		healthy := meta.FindStatusCondition(schedule.Status.Conditions, ConditionHealthy)
+		Expect(healthy.Status).To(Equal(metav1.ConditionFalse))
+		Expect(healthy.Message).To(ContainSubstring("AuthenticationFailed"))
+		Expect(schedule.Status.LastFailureTime.Time).To(Equal(base.Add(time.Hour)))
+		Expect(schedule.Status.LastRunPhase).To(Equal(PhaseCancelled))
+
+		// Pruned runs do not reset the last known outcome
+		setRunStatus(schedule, groupRuns(nil))
+		Expect(meta.IsStatusConditionFalse(schedule.Status.Conditions, ConditionHealthy)).To(BeTrue())
+		Expect(schedule.Status.LastFailureTime).NotTo(BeNil())
+	})
+
+	It("should track spec.suspend", func() {
+		schedule := &datamoverv1alpha1.DataMoverSchedule{}
+		schedule.Spec.Suspend = true
+		setSuspendedCondition(schedule)
+		Expect(meta.IsStatusConditionTrue(schedule.Status.Conditions, ConditionSuspended)).To(BeTrue())
+
+		schedule.Spec.Suspend = false
+		setSuspendedCondition(schedule)
+		Expect(meta.IsStatusConditionFalse(schedule.Status.Conditions, ConditionSuspended)).To(BeTrue())
+	})
+})
@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	datamoverv1alpha1 "a-cup-of.coffee/datamover-operator/api/v1alpha1"
+)
+
+const (
+	// LabelSchedule names the DataMoverSchedule that created a DataMover
+	LabelSchedule = "datamoverschedule"
+	// LabelScheduledTime holds the Unix time a DataMover was scheduled at
+	LabelScheduledTime = "datamoverschedule-schedule"
+)
+
+// runTemplate returns the template of the DataMovers created by a schedule.
+// Schedules without a dataMoverTemplate use their deprecated fields.
+func runTemplate(schedule *datamoverv1alpha1.DataMoverSchedule) *datamoverv1alpha1.DataMoverTemplateSpec {
+	if schedule.Spec.DataMoverTemplate != nil {
+		return schedule.Spec.DataMoverTemplate.DeepCopy()
+	}
+	return &datamoverv1alpha1.DataMoverTemplateSpec{
+		Spec: datamoverv1alpha1.DataMoverSpec{
+			SourcePVC:            schedule.Spec.SourcePvc,
+			SecretName:           schedule.Spec.SecretName,
+			AddTimestampPrefix:   schedule.Spec.AddTimestampPrefix,
+			DeletePvcAfterBackup: schedule.Spec.DeletePvcAfterBackup,
+			AdditionalEnv:        schedule.Spec.AdditionalEnv,
+			Image:                schedule.Spec.Image,
+		},
+	}
+}
+
+// newScheduledDataMover builds the DataMover of the run scheduled at the given time.
+// The labels and annotations of the template are kept, the schedule labels take precedence.
+func newScheduledDataMover(
+	schedule *datamoverv1alpha1.DataMoverSchedule,
+	name string,
+	scheduledTime time.Time,
+) *datamoverv1alpha1.DataMover {
+	template := runTemplate(schedule)
+
+	labels := template.Metadata.Labels
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[LabelSchedule] = schedule.Name
+	labels[LabelScheduledTime] = fmt.Sprintf("%d", scheduledTime.Unix())
+
+	return &datamoverv1alpha1.DataMover{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   schedule.Namespace,
+			Labels:      labels,
+			Annotations: template.Metadata.Annotations,
+		},
+		Spec: template.Spec,
+	}
+}
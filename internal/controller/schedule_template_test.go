@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	datamoverv1alpha1 "a-cup-of.coffee/datamover-operator/api/v1alpha1"
+)
+
+var _ = Describe("Schedule template", func() {
+	scheduledTime := time.Unix(1700000000, 0)
+
+	It("should build runs from the deprecated fields", func() {
+		schedule := &datamoverv1alpha1.DataMoverSchedule{
+			ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "apps"},
+			Spec: datamoverv1alpha1.DataMoverScheduleSpec{
+				SourcePvc:            "app-data",
+				SecretName:           "s3-credentials",
+				DeletePvcAfterBackup: true,
+			},
+		}
+
+		dm := newScheduledDataMover(schedule, "nightly-1700000000", scheduledTime)
+		Expect(dm.Namespace).To(Equal("apps"))
+		Expect(dm.Spec.SourcePVC).To(Equal("app-data"))
+		Expect(dm.Spec.SecretName).To(Equal("s3-credentials"))
+		Expect(dm.Spec.DeletePvcAfterBackup).To(BeTrue())
+		Expect(dm.Labels).To(Equal(map[string]string{
+			LabelSchedule:      "nightly",
+			LabelScheduledTime: "1700000000",
+		}))
+	})
+
+	It("should copy the template and its metadata", func() {
+		schedule := &datamoverv1alpha1.DataMoverSchedule{
+			ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "apps"},
+			Spec: datamoverv1alpha1.DataMoverScheduleSpec{
+				// Ignored in favor of the template
+				SourcePvc: "legacy-data",
+				DataMoverTemplate: &datamoverv1alpha1.DataMoverTemplateSpec{
+					Metadata: datamoverv1alpha1.DataMoverTemplateMetadata{
+						Labels:      map[string]string{"team": "storage", LabelSchedule: "other"},
+						Annotations: map[string]string{"owner": "storage@example.com"},
+					},
+					Spec: datamoverv1alpha1.DataMoverSpec{
+						SourcePVC:  "app-data",
+						SecretName: "s3-credentials",
+						Verify:     true,
+						Transfer:   &datamoverv1alpha1.TransferSpec{Mode: datamoverv1alpha1.TransferModeCopy},
+					},
+				},
+			},
+		}
+
+		dm := newScheduledDataMover(schedule, "nightly-1700000000", scheduledTime)
+		Expect(dm.Spec.SourcePVC).To(Equal("app-data"))
+		Expect(dm.Spec.Verify).To(BeTrue())
+		Expect(dm.Spec.Transfer.Mode).To(Equal(datamoverv1alpha1.TransferModeCopy))
+		Expect(dm.Labels).To(HaveKeyWithValue("team", "storage"))
+		Expect(dm.Labels).To(HaveKeyWithValue(LabelSchedule, "nightly"))
+		Expect(dm.Annotations).To(HaveKeyWithValue("owner", "storage@example.com"))
+
+		// The schedule itself is left untouched
+		Expect(schedule.Spec.DataMoverTemplate.Metadata.Labels).To(HaveKeyWithValue(LabelSchedule, "other"))
+	})
+})
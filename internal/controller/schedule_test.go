@@ -0,0 +1,112 @@
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Cron schedules", func() {
+	paris := "Europe/Paris"
+	location, _ := time.LoadLocation(paris)
+
+	DescribeTable("should accept the standard cron syntax",
+		func(spec string) {
+			_, err := parseSchedule(spec, nil, "")
+			Expect(err).NotTo(HaveOccurred())
+		},
+		Entry("ranges", "30 2 * * 1-5"),
+		Entry("lists", "0 0 1,15 * *"),
+		Entry("steps", "*/15 8-18 * * *"),
+		Entry("names", "0 3 * JAN-JUN MON-FRI"),
+		Entry("macro", "@daily"),
+		Entry("interval", "@every 6h"),
+		Entry("time zone prefix", "CRON_TZ=Europe/Paris 30 2 * * *"),
+	)
+
+	DescribeTable("should reject invalid schedules",
+		func(spec string, timeZone *string) {
+			_, err := parseSchedule(spec, timeZone, "")
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("wrong field count", "0 2 * *", nil),
+		Entry("out of range", "0 25 * * *", nil),
+		Entry("unknown time zone", "0 2 * * *", &[]string{"Mars/Olympus"}[0]),
+		Entry("time zone set twice", "TZ=UTC 0 2 * * *", &paris),
+	)
+
+	It("should evaluate the schedule in its time zone", func() {
+		schedule, err := parseSchedule("30 2 * * 1-5", &paris, "")
+		Expect(err).NotTo(HaveOccurred())
+
+		// Friday 28 March 2025, then Monday 31 March after the weekend
+		next := schedule.Next(time.Date(2025, 3, 27, 12, 0, 0, 0, location))
+		Expect(next).To(BeTemporally("==", time.Date(2025, 3, 28, 1, 30, 0, 0, time.UTC)))
+		next = schedule.Next(next)
+		Expect(next).To(BeTemporally("==", time.Date(2025, 3, 31, 0, 30, 0, 0, time.UTC)))
+	})
+
+	It("should shift runs skipped when the clocks spring forward", func() {
+		schedule, err := parseSchedule("30 2 * * *", &paris, "")
+		Expect(err).NotTo(HaveOccurred())
+
+		// 02:30 does not exist on 30 March 2025 in Paris, the run happens at 03:30 CEST
+		next := schedule.Next(time.Date(2025, 3, 29, 12, 0, 0, 0, location))
+		Expect(next).To(BeTemporally("==", time.Date(2025, 3, 30, 1, 30, 0, 0, time.UTC)))
+		next = schedule.Next(next)
+		Expect(next).To(BeTemporally("==", time.Date(2025, 3, 31, 0, 30, 0, 0, time.UTC)))
+	})
+
+	It("should run once when the clocks fall back", func() {
+		schedule, err := parseSchedule("30 2 * * *", &paris, "")
+		Expect(err).NotTo(HaveOccurred())
+
+		// 02:30 happens twice on 26 October 2025 in Paris
+		next := schedule.Next(time.Date(2025, 10, 25, 12, 0, 0, 0, location))
+		Expect(next.In(location).Day()).To(Equal(26))
+		next = schedule.Next(next)
+		Expect(next).To(BeTemporally("==", time.Date(2025, 10, 27, 1, 30, 0, 0, time.UTC)))
+	})
+
+	It("should not depend on the wall clock for fixed intervals", func() {
+		schedule, err := parseSchedule("@every 6h", &paris, "")
+		Expect(err).NotTo(HaveOccurred())
+
+		now := time.Date(2025, 10, 26, 0, 0, 0, 0, time.UTC)
+		Expect(schedule.Next(now)).To(Equal(now.Add(6 * time.Hour)))
+	})
+
+	It("should list the runs due since the last one", func() {
+		schedule, err := parseSchedule("0 * * * *", &[]string{"UTC"}[0], "")
+		Expect(err).NotTo(HaveOccurred())
+
+		now := time.Date(2025, 6, 3, 9, 30, 0, 0, time.UTC)
+		runs, tooMany := schedule.dueRuns(time.Date(2025, 6, 3, 6, 0, 0, 0, time.UTC), now)
+		Expect(tooMany).To(BeFalse())
+		Expect(runs).To(Equal([]time.Time{
+			time.Date(2025, 6, 3, 7, 0, 0, 0, time.UTC),
+			time.Date(2025, 6, 3, 8, 0, 0, 0, time.UTC),
+			time.Date(2025, 6, 3, 9, 0, 0, 0, time.UTC),
+		}))
+
+		runs, _ = schedule.dueRuns(time.Date(2025, 6, 3, 9, 0, 0, 0, time.UTC), now)
+		Expect(runs).To(BeEmpty())
+	})
+
+	It("should cap the catch-up window", func() {
+		daily, err := parseSchedule("0 2 * * *", &[]string{"UTC"}[0], "")
+		Expect(err).NotTo(HaveOccurred())
+
+		// Runs older than the catch-up window are not looked for
+		now := time.Date(2025, 6, 10, 9, 0, 0, 0, time.UTC)
+		runs, _ := daily.dueRuns(time.Date(2025, 6, 1, 2, 0, 0, 0, time.UTC), now)
+		Expect(runs).To(Equal([]time.Time{time.Date(2025, 6, 10, 2, 0, 0, 0, time.UTC)}))
+
+		everyMinute, err := parseSchedule("* * * * *", nil, "")
+		Expect(err).NotTo(HaveOccurred())
+		runs, tooMany := everyMinute.dueRuns(now.Add(-3*time.Hour), now)
+		Expect(tooMany).To(BeTrue())
+		Expect(runs).To(BeEmpty())
+	})
+})
@@ -0,0 +1,43 @@
+package controller
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	datamoverv1alpha1 "a-cup-of.coffee/datamover-operator/api/v1alpha1"
+)
+
+// statusEffects holds the metrics and events reporting a phase transition until the status
+// recording it is written. A reconcile whose status patch conflicts is retried from the
+// latest state, and would otherwise report the same transition again.
+type statusEffects struct {
+	mu      sync.Mutex
+	pending map[types.NamespacedName][]func()
+}
+
+// add queues an effect for the status of the DataMover being reconciled
+func (e *statusEffects) add(dm *datamoverv1alpha1.DataMover, effect func()) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.pending == nil {
+		e.pending = map[types.NamespacedName][]func(){}
+	}
+	key := types.NamespacedName{Name: dm.Name, Namespace: dm.Namespace}
+	e.pending[key] = append(e.pending[key], effect)
+}
+
+// take returns and clears the effects queued for a DataMover
+func (e *statusEffects) take(key types.NamespacedName) []func() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	effects := e.pending[key]
+	delete(e.pending, key)
+	return effects
+}
+
+// afterStatusWrite runs an effect once the status changes of the current reconcile were written.
+// Effects of a reconcile whose status patch failed are dropped.
+func (r *DataMoverReconciler) afterStatusWrite(dm *datamoverv1alpha1.DataMover, effect func()) {
+	r.statusEffects.add(dm, effect)
+}
@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	datamoverv1alpha1 "a-cup-of.coffee/datamover-operator/api/v1alpha1"
+)
+
+// Environment variables read by the mover entrypoint to build the rclone command line
+const (
+	EnvTransferMode       = "TRANSFER_MODE"
+	EnvVerify             = "VERIFY"
+	EnvDryRun             = "DRY_RUN"
+	EnvFilterRules        = "FILTER_RULES"
+	EnvMaxAge             = "MAX_AGE"
+	EnvMaxSize            = "MAX_SIZE"
+	EnvBandwidthLimit     = "BANDWIDTH_LIMIT"
+	EnvTransfers          = "TRANSFERS"
+	EnvCheckers           = "CHECKERS"
+	EnvMultipartChunkSize = "MULTIPART_CHUNK_SIZE"
+)
+
+// transferMode returns the transfer mode of a DataMover, defaulting to Sync
+func transferMode(dm *datamoverv1alpha1.DataMover) datamoverv1alpha1.TransferMode {
+	if dm.Spec.Transfer == nil || dm.Spec.Transfer.Mode == "" {
+		return datamoverv1alpha1.TransferModeSync
+	}
+	return dm.Spec.Transfer.Mode
+}
+
+// dryRunEnabled reports whether the transfer must only be estimated.
+// Check runs never write to the destination so they are never dry runs.
+func dryRunEnabled(dm *datamoverv1alpha1.DataMover) bool {
+	return dm.Spec.DryRun && transferMode(dm) != datamoverv1alpha1.TransferModeCheck
+}
+
+// verificationEnabled reports whether the upload must be verified once transferred.
+// Check runs and dry runs never write to the destination so there is nothing to verify.
+func verificationEnabled(dm *datamoverv1alpha1.DataMover) bool {
+	return dm.Spec.Verify && transferMode(dm) != datamoverv1alpha1.TransferModeCheck && !dryRunEnabled(dm)
+}
+
+// buildFilterRules converts include/exclude patterns into an rclone filter file.
+// Excludes come first so they win over includes, and a trailing catch-all
+// exclude is added when includes are set so that only included files are transferred.
+func buildFilterRules(transfer *datamoverv1alpha1.TransferSpec) string {
+	if transfer == nil || (len(transfer.Include) == 0 && len(transfer.Exclude) == 0) {
+		return ""
+	}
+
+	rules := make([]string, 0, len(transfer.Exclude)+len(transfer.Include)+1)
+	for _, pattern := range transfer.Exclude {
+		rules = append(rules, "- "+pattern)
+	}
+	for _, pattern := range transfer.Include {
+		rules = append(rules, "+ "+pattern)
+	}
+	if len(transfer.Include) > 0 {
+		rules = append(rules, "- **")
+	}
+
+	return strings.Join(rules, "\n")
+}
+
+// transferEnvVars returns the environment variables passing the transfer options to the mover
+func transferEnvVars(transfer *datamoverv1alpha1.TransferSpec) []corev1.EnvVar {
+	if transfer == nil {
+		return nil
+	}
+
+	envVars := make([]corev1.EnvVar, 0)
+	appendIfSet := func(name, value string) {
+		if value != "" {
+			envVars = append(envVars, corev1.EnvVar{Name: name, Value: value})
+		}
+	}
+
+	appendIfSet(EnvFilterRules, buildFilterRules(transfer))
+	appendIfSet(EnvMaxAge, transfer.MaxAge)
+	appendIfSet(EnvMaxSize, transfer.MaxSize)
+	appendIfSet(EnvBandwidthLimit, transfer.BandwidthLimit)
+	if transfer.Transfers != nil {
+		appendIfSet(EnvTransfers, strconv.Itoa(int(*transfer.Transfers)))
+	}
+	if transfer.Checkers != nil {
+		appendIfSet(EnvCheckers, strconv.Itoa(int(*transfer.Checkers)))
+	}
+	appendIfSet(EnvMultipartChunkSize, transfer.MultipartChunkSize)
+
+	return envVars
+}
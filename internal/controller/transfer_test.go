@@ -0,0 +1,54 @@
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+
+	datamoverv1alpha1 "a-cup-of.coffee/datamover-operator/api/v1alpha1"
+)
+
+var _ = Describe("Transfer options", func() {
+	It("should not emit anything when no transfer options are set", func() {
+		Expect(transferEnvVars(nil)).To(BeEmpty())
+		Expect(buildFilterRules(&datamoverv1alpha1.TransferSpec{})).To(BeEmpty())
+	})
+
+	It("should put excludes before includes and close includes with a catch-all", func() {
+		rules := buildFilterRules(&datamoverv1alpha1.TransferSpec{
+			Include: []string{"*.db", "/logs/**"},
+			Exclude: []string{"*.tmp"},
+		})
+		Expect(rules).To(Equal("- *.tmp\n+ *.db\n+ /logs/**\n- **"))
+	})
+
+	It("should only emit excludes when no includes are set", func() {
+		rules := buildFilterRules(&datamoverv1alpha1.TransferSpec{
+			Exclude: []string{"cache/**"},
+		})
+		Expect(rules).To(Equal("- cache/**"))
+	})
+
+	It("should pass every tuning option explicitly", func() {
+		transfers := int32(8)
+		checkers := int32(16)
+		envVars := transferEnvVars(&datamoverv1alpha1.TransferSpec{
+			Exclude:            []string{"*.tmp"},
+			MaxAge:             "7d",
+			MaxSize:            "2G",
+			BandwidthLimit:     "08:00,512k 19:00,off",
+			Transfers:          &transfers,
+			Checkers:           &checkers,
+			MultipartChunkSize: "16M",
+		})
+		Expect(envVars).To(ConsistOf(
+			corev1.EnvVar{Name: EnvFilterRules, Value: "- *.tmp"},
+			corev1.EnvVar{Name: EnvMaxAge, Value: "7d"},
+			corev1.EnvVar{Name: EnvMaxSize, Value: "2G"},
+			corev1.EnvVar{Name: EnvBandwidthLimit, Value: "08:00,512k 19:00,off"},
+			corev1.EnvVar{Name: EnvTransfers, Value: "8"},
+			corev1.EnvVar{Name: EnvCheckers, Value: "16"},
+			corev1.EnvVar{Name: EnvMultipartChunkSize, Value: "16M"},
+		))
+	})
+})
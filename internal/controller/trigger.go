@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	datamoverv1alpha1 "a-cup-of.coffee/datamover-operator/api/v1alpha1"
+)
+
+const (
+	// AnnotationTrigger starts a manual run of a schedule whenever its value changes
+	AnnotationTrigger = "datamover.a-cup-of.coffee/trigger"
+	// LabelTrigger marks the runs started on demand rather than by the cron schedule
+	LabelTrigger = "datamoverschedule-trigger"
+	// TriggerManual is the value of LabelTrigger for runs started with AnnotationTrigger
+	TriggerManual = "manual"
+
+	// EventTriggered is emitted when a manual run is requested with AnnotationTrigger
+	EventTriggered = "Triggered"
+)
+
+// pendingTrigger returns the trigger token of a schedule that was not acted upon yet
+func pendingTrigger(schedule *datamoverv1alpha1.DataMoverSchedule) string {
+	token := schedule.Annotations[AnnotationTrigger]
+	if token == "" || token == schedule.Status.LastTriggerToken {
+		return ""
+	}
+	return token
+}
+
+// manualRunName returns the name of the run started by a trigger token.
+// The name only depends on the token, so a token never starts two runs.
+func manualRunName(schedule *datamoverv1alpha1.DataMoverSchedule, token string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(token))
+	return fmt.Sprintf("%s-manual-%08x", schedule.Name, h.Sum32())
+}
+
+// newManualDataMover builds the DataMover of a run started by a trigger token
+func newManualDataMover(
+	schedule *datamoverv1alpha1.DataMoverSchedule,
+	token string,
+	now time.Time,
+) *datamoverv1alpha1.DataMover {
+	dataMover := newScheduledDataMover(schedule, manualRunName(schedule, token), now)
+	dataMover.Labels[LabelTrigger] = TriggerManual
+	if dataMover.Annotations == nil {
+		dataMover.Annotations = map[string]string{}
+	}
+	dataMover.Annotations[AnnotationTrigger] = token
+	return dataMover
+}
+
+// triggerRun starts the manual run requested by a trigger token, following the concurrency
+// policy, and acknowledges the token in the status
+func (r *DataMoverScheduleReconciler) triggerRun(
+	ctx context.Context,
+	schedule *datamoverv1alpha1.DataMoverSchedule,
+	active []*datamoverv1alpha1.DataMover,
+	token string,
+) error {
+	logger := log.FromContext(ctx)
+	logger.Info("manual run requested", "token", token)
+	r.Recorder.Eventf(schedule, corev1.EventTypeNormal, EventTriggered, "Manual run requested with token %q", token)
+
+	now := time.Now()
+	if _, err := r.startRun(ctx, schedule, active, newManualDataMover(schedule, token, now), now); err != nil {
+		return err
+	}
+	schedule.Status.LastTriggerToken = token
+	return nil
+}
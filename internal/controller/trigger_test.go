@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	datamoverv1alpha1 "a-cup-of.coffee/datamover-operator/api/v1alpha1"
+)
+
+var _ = Describe("Manual trigger", func() {
+	var schedule *datamoverv1alpha1.DataMoverSchedule
+
+	BeforeEach(func() {
+		schedule = &datamoverv1alpha1.DataMoverSchedule{
+			ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "apps"},
+			Spec: datamoverv1alpha1.DataMoverScheduleSpec{
+				DataMoverTemplate: &datamoverv1alpha1.DataMoverTemplateSpec{
+					Spec: datamoverv1alpha1.DataMoverSpec{SourcePVC: "app-data", SecretName: "s3-credentials"},
+				},
+			},
+		}
+	})
+
+	It("should only act on new tokens", func() {
+		Expect(pendingTrigger(schedule)).To(BeEmpty())
+
+		schedule.Annotations = map[string]string{AnnotationTrigger: "before-upgrade"}
+		Expect(pendingTrigger(schedule)).To(Equal("before-upgrade"))
+
+		schedule.Status.LastTriggerToken = "before-upgrade"
+		Expect(pendingTrigger(schedule)).To(BeEmpty())
+	})
+
+	It("should name manual runs after their token", func() {
+		name := manualRunName(schedule, "before-upgrade")
+		Expect(name).To(HavePrefix("nightly-manual-"))
+		Expect(manualRunName(schedule, "before-upgrade")).To(Equal(name))
+		Expect(manualRunName(schedule, "after-upgrade")).NotTo(Equal(name))
+	})
+
+	It("should label manual runs", func() {
+		dm := newManualDataMover(schedule, "before-upgrade", time.Unix(1700000000, 0))
+		Expect(dm.Spec.SourcePVC).To(Equal("app-data"))
+		Expect(dm.Labels).To(HaveKeyWithValue(LabelSchedule, "nightly"))
+		Expect(dm.Labels).To(HaveKeyWithValue(LabelTrigger, TriggerManual))
+		Expect(dm.Annotations).To(HaveKeyWithValue(AnnotationTrigger, "before-upgrade"))
+	})
+})
@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	datamoverv1alpha1 "a-cup-of.coffee/datamover-operator/api/v1alpha1"
+	"a-cup-of.coffee/datamover-operator/internal/metrics"
+)
+
+// EventExpired is emitted when a finished DataMover is deleted after its TTL
+const EventExpired = "Expired"
+
+// isFinished reports whether a DataMover reached a terminal phase
+func isFinished(phase string) bool {
+	return phase == PhaseCompleted || phase == PhaseFailed || phase == PhaseCancelled
+}
+
+// ttlAfterFinished returns how long a finished DataMover is kept, if it expires at all
+func (r *DataMoverReconciler) ttlAfterFinished(dm *datamoverv1alpha1.DataMover) *time.Duration {
+	ttl := dm.Spec.TTLSecondsAfterFinished
+	if ttl == nil {
+		ttl = r.DefaultTTLSecondsAfterFinished
+	}
+	if ttl == nil {
+		return nil
+	}
+	duration := time.Duration(*ttl) * time.Second
+	return &duration
+}
+
+// finishedAt returns when a DataMover entered its terminal phase.
+// DataMovers that finished before phase timings were recorded fall back to their creation time.
+func finishedAt(dm *datamoverv1alpha1.DataMover) time.Time {
+	if timing := findPhaseTiming(dm.Status.PhaseTimings, dm.Status.Phase); timing != nil {
+		return timing.StartTime.Time
+	}
+	return dm.CreationTimestamp.Time
+}
+
+// isOwnCloneRetained reports whether the clone of a DataMover is kept by the retention policy,
+// in which case the DataMover stays to enforce it
+func isOwnCloneRetained(dm *datamoverv1alpha1.DataMover) bool {
+	for _, clone := range dm.Status.RetainedClones {
+		if clone.Name == dm.Status.RestoredPVCName {
+			return true
+		}
+	}
+	return false
+}
+
+// expireFinished deletes a finished DataMover once its TTL elapsed, requeueing until then.
+// The result of the phase step is returned unchanged when the DataMover does not expire.
+func (r *DataMoverReconciler) expireFinished(
+	ctx context.Context,
+	dm *datamoverv1alpha1.DataMover,
+	result ctrl.Result,
+) (ctrl.Result, error) {
+	ttl := r.ttlAfterFinished(dm)
+	if ttl == nil || isOwnCloneRetained(dm) {
+		return result, nil
+	}
+
+	if remaining := time.Until(finishedAt(dm).Add(*ttl)); remaining > 0 {
+		if result.RequeueAfter == 0 || remaining < result.RequeueAfter {
+			result.RequeueAfter = remaining
+		}
+		return result, nil
+	}
+
+	logger := log.FromContext(ctx)
+	logger.Info("Deleting finished DataMover after its TTL", "phase", dm.Status.Phase, "ttl", ttl.String())
+
+	// The mover Job is owned by the DataMover and deleted with it, the snapshot and clone are not.
+	// Nothing else would delete the clone once the DataMover is gone, unless it is retained.
+	if err := r.deleteSourceSnapshot(ctx, dm); err != nil {
+		logger.Error(err, "Failed to delete source snapshot", "snapshotName", dm.Status.SnapshotName)
+		metrics.RecordError("snapshot_delete_failed", dm.Status.Phase, dm.Namespace)
+		return ctrl.Result{}, err
+	}
+	if err := r.deleteWorkingPVC(ctx, dm); err != nil {
+		logger.Error(err, "Failed to delete cloned PVC")
+		metrics.RecordError("pvc_delete_failed", dm.Status.Phase, dm.Namespace)
+		metrics.RecordPVCCleanupOperation("failure", dm.Namespace)
+		return ctrl.Result{}, err
+	}
+
+	r.Recorder.Eventf(dm, corev1.EventTypeNormal, EventExpired,
+		"Deleting DataMover finished for more than %s", ttl.String())
+	if err := r.Delete(ctx, dm, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil {
+		logger.Error(err, "Failed to delete expired DataMover")
+		metrics.RecordError("datamover_delete_failed", dm.Status.Phase, dm.Namespace)
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	return ctrl.Result{}, nil
+}
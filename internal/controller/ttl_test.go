@@ -0,0 +1,138 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	datamoverv1alpha1 "a-cup-of.coffee/datamover-operator/api/v1alpha1"
+	"a-cup-of.coffee/datamover-operator/internal/metrics"
+)
+
+var _ = Describe("TTL after finished", func() {
+	It("should only expire finished DataMovers", func() {
+		Expect(isFinished(PhaseCompleted)).To(BeTrue())
+		Expect(isFinished(PhaseFailed)).To(BeTrue())
+		Expect(isFinished(PhaseCancelled)).To(BeTrue())
+		Expect(isFinished(PhaseCreatingPod)).To(BeFalse())
+	})
+
+	It("should fall back to the controller default", func() {
+		r := &DataMoverReconciler{}
+		dm := &datamoverv1alpha1.DataMover{}
+		Expect(r.ttlAfterFinished(dm)).To(BeNil())
+
+		r.DefaultTTLSecondsAfterFinished = &[]int32{3600}[0]
+		Expect(*r.ttlAfterFinished(dm)).To(Equal(time.Hour))
+
+		dm.Spec.TTLSecondsAfterFinished = &[]int32{0}[0]
+		Expect(*r.ttlAfterFinished(dm)).To(BeZero())
+	})
+
+	It("should count from the start of the terminal phase", func() {
+		created := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+		completed := created.Add(time.Hour)
+		dm := &datamoverv1alpha1.DataMover{
+			ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created)},
+		}
+		Expect(finishedAt(dm)).To(Equal(created))
+
+		dm.Status.Phase = PhaseCompleted
+		dm.Status.PhaseTimings = []datamoverv1alpha1.PhaseTiming{
+			{Phase: PhaseCreatingPod, StartTime: metav1.NewTime(created), EndTime: &metav1.Time{Time: completed}},
+			{Phase: PhaseCompleted, StartTime: metav1.NewTime(completed)},
+		}
+		Expect(finishedAt(dm)).To(Equal(completed))
+	})
+
+	It("should count a failure once while the DataMover waits for its TTL", func() {
+		r := &DataMoverReconciler{Recorder: record.NewFakeRecorder(10)}
+		dm := &datamoverv1alpha1.DataMover{ObjectMeta: metav1.ObjectMeta{Namespace: "ttl-failures"}}
+		dm.Status.Phase = PhaseCreatingPod
+		failures := metrics.DataSyncOperationsTotal.WithLabelValues("failure", "ttl-failures")
+
+		// Each reconcile runs the effects of its step once the status is written
+		for range 2 {
+			r.failDataMover(dm, ReasonJobFailed, "Rclone sync failed.")
+			for _, effect := range r.statusEffects.take(client.ObjectKeyFromObject(dm)) {
+				effect()
+			}
+		}
+		Expect(dm.Status.Phase).To(Equal(PhaseFailed))
+		Expect(testutil.ToFloat64(failures)).To(Equal(1.0))
+	})
+
+	It("should keep DataMovers whose clone is retained", func() {
+		dm := &datamoverv1alpha1.DataMover{
+			Status: datamoverv1alpha1.DataMoverStatus{RestoredPVCName: "app-data-cloned-1234abcd"},
+		}
+		Expect(isOwnCloneRetained(dm)).To(BeFalse())
+
+		dm.Status.RetainedClones = []datamoverv1alpha1.RetainedClone{{Name: "app-data-cloned-1234abcd"}}
+		Expect(isOwnCloneRetained(dm)).To(BeTrue())
+	})
+
+	Context("when the TTL elapsed", func() {
+		var (
+			c  client.Client
+			r  *DataMoverReconciler
+			dm *datamoverv1alpha1.DataMover
+		)
+
+		clone := func(labels map[string]string) *corev1.PersistentVolumeClaim {
+			return &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "app-data-cloned-1234abcd", Namespace: "default", Labels: labels},
+			}
+		}
+
+		expire := func(objects ...client.Object) {
+			scheme := runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+			Expect(datamoverv1alpha1.AddToScheme(scheme)).To(Succeed())
+			dm = &datamoverv1alpha1.DataMover{
+				ObjectMeta: metav1.ObjectMeta{Name: "one-off", Namespace: "default"},
+				Spec: datamoverv1alpha1.DataMoverSpec{
+					SourcePVC:               "app-data",
+					TTLSecondsAfterFinished: &[]int32{0}[0],
+				},
+				Status: datamoverv1alpha1.DataMoverStatus{
+					Phase:           PhaseFailed,
+					RestoredPVCName: "app-data-cloned-1234abcd",
+				},
+			}
+			c = fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(objects, dm)...).Build()
+			r = &DataMoverReconciler{Client: c, Recorder: record.NewFakeRecorder(10)}
+
+			_, err := r.expireFinished(context.Background(), dm, ctrl.Result{})
+			Expect(err).NotTo(HaveOccurred())
+			err = c.Get(context.Background(), client.ObjectKeyFromObject(dm), &datamoverv1alpha1.DataMover{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		}
+
+		It("should delete the clone left by the DataMover by default", func() {
+			pvc := clone(nil)
+			expire(pvc)
+			err := c.Get(context.Background(), client.ObjectKeyFromObject(pvc), &corev1.PersistentVolumeClaim{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should leave retained clones to the retention policy", func() {
+			pvc := clone(map[string]string{LabelRetained: "true"})
+			expire(pvc)
+			Expect(c.Get(context.Background(), client.ObjectKeyFromObject(pvc), &corev1.PersistentVolumeClaim{})).
+				To(Succeed())
+		})
+	})
+})
@@ -0,0 +1,337 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	datamoverv1alpha1 "a-cup-of.coffee/datamover-operator/api/v1alpha1"
+)
+
+const (
+	// ConditionInWindow reports whether scheduled runs may start now
+	ConditionInWindow = "InWindow"
+
+	ReasonWindowOpen    = "WindowOpen"
+	ReasonOutsideWindow = "OutsideWindow"
+	ReasonBlackout      = "Blackout"
+
+	// EventRunDeferred is emitted when a scheduled run waits for a window or the end of a blackout period
+	EventRunDeferred = "RunDeferred"
+
+	// maxWindowIterations bounds the search for the time runs are allowed again, which alternates
+	// between blackout periods and window openings
+	maxWindowIterations = 100
+)
+
+// dailyWindow is a parsed backup window, with its times of day in minutes after midnight
+type dailyWindow struct {
+	start, end int
+	// days the window opens on, every day when nil
+	days     map[time.Weekday]bool
+	location *time.Location
+}
+
+// closedPeriod explains why scheduled runs may not start, and when they may start again
+type closedPeriod struct {
+	reason     string
+	skipReason string
+	message    string
+	// until is the time runs are allowed again, zero when no window opens
+	until time.Time
+}
+
+// windowPolicy returns the window policy of a schedule, defaulting to Defer
+func windowPolicy(schedule *datamoverv1alpha1.DataMoverSchedule) datamoverv1alpha1.WindowPolicy {
+	if schedule.Spec.WindowPolicy == "" {
+		return datamoverv1alpha1.DeferOutsideWindow
+	}
+	return schedule.Spec.WindowPolicy
+}
+
+// parseWindows parses the backup windows of a schedule, in the time zone of the schedule by default
+func parseWindows(schedule *datamoverv1alpha1.DataMoverSchedule) ([]dailyWindow, error) {
+	windows := make([]dailyWindow, 0, len(schedule.Spec.Windows))
+	for i, window := range schedule.Spec.Windows {
+		var parsed dailyWindow
+		var err error
+		if parsed.start, err = parseTimeOfDay(window.Start); err != nil {
+			return nil, fmt.Errorf("window %d: invalid start: %w", i, err)
+		}
+		if parsed.end, err = parseTimeOfDay(window.End); err != nil {
+			return nil, fmt.Errorf("window %d: invalid end: %w", i, err)
+		}
+
+		timeZone := window.TimeZone
+		if timeZone == nil {
+			timeZone = schedule.Spec.TimeZone
+		}
+		parsed.location = time.Local
+		if timeZone != nil {
+			if parsed.location, err = time.LoadLocation(*timeZone); err != nil {
+				return nil, fmt.Errorf("window %d: unknown time zone %q: %w", i, *timeZone, err)
+			}
+		}
+
+		for _, day := range window.Days {
+			weekday, err := parseWeekday(day)
+			if err != nil {
+				return nil, fmt.Errorf("window %d: %w", i, err)
+			}
+			if parsed.days == nil {
+				parsed.days = map[time.Weekday]bool{}
+			}
+			parsed.days[weekday] = true
+		}
+		windows = append(windows, parsed)
+	}
+	return windows, nil
+}
+
+// parseTimeOfDay returns the minutes after midnight of a HH:MM time
+func parseTimeOfDay(value string) (int, error) {
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("expected HH:MM, got %q", value)
+	}
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
+
+// parseWeekday returns the day of the week of its English name
+func parseWeekday(day datamoverv1alpha1.Weekday) (time.Weekday, error) {
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		if weekday.String() == string(day) {
+			return weekday, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown day %q", day)
+}
+
+// opening returns when the window opens on the day of the given date, in the time zone of the window
+func (w dailyWindow) opening(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, w.start/60, w.start%60, 0, 0, w.location)
+}
+
+// contains reports whether the window is open at the given time.
+// A window ending before its start may have opened the day before.
+func (w dailyWindow) contains(t time.Time) bool {
+	local := t.In(w.location)
+	for _, offset := range []int{0, -1} {
+		open := w.opening(local.Year(), local.Month(), local.Day()+offset)
+		closeDay := open.Day()
+		if w.end <= w.start {
+			closeDay++
+		}
+		closing := time.Date(open.Year(), open.Month(), closeDay, w.end/60, w.end%60, 0, 0, w.location)
+		if w.opensOn(open.Weekday()) && !t.Before(open) && t.Before(closing) {
+			return true
+		}
+	}
+	return false
+}
+
+// nextOpening returns the first time the window opens strictly after the given time
+func (w dailyWindow) nextOpening(t time.Time) time.Time {
+	local := t.In(w.location)
+	for offset := 0; offset <= 7; offset++ {
+		open := w.opening(local.Year(), local.Month(), local.Day()+offset)
+		if open.After(t) && w.opensOn(open.Weekday()) {
+			return open
+		}
+	}
+	return time.Time{}
+}
+
+// opensOn reports whether the window opens on a day of the week
+func (w dailyWindow) opensOn(day time.Weekday) bool {
+	return w.days == nil || w.days[day]
+}
+
+// inWindows reports whether runs may start at the given time, which is always the case without windows
+func inWindows(windows []dailyWindow, t time.Time) bool {
+	if len(windows) == 0 {
+		return true
+	}
+	for _, w := range windows {
+		if w.contains(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// nextWindowOpening returns the first time a window opens after the given time
+func nextWindowOpening(windows []dailyWindow, t time.Time) time.Time {
+	var next time.Time
+	for _, w := range windows {
+		if opening := w.nextOpening(t); !opening.IsZero() && (next.IsZero() || opening.Before(next)) {
+			next = opening
+		}
+	}
+	return next
+}
+
+// activeBlackout returns the blackout period in effect at the given time which ends last
+func activeBlackout(blackouts []datamoverv1alpha1.BlackoutPeriod, t time.Time) *datamoverv1alpha1.BlackoutPeriod {
+	var active *datamoverv1alpha1.BlackoutPeriod
+	for i := range blackouts {
+		blackout := &blackouts[i]
+		if t.Before(blackout.Spec.Start.Time) || !t.Before(blackout.Spec.End.Time) {
+			continue
+		}
+		if active == nil || blackout.Spec.End.After(active.Spec.End.Time) {
+			active = blackout
+		}
+	}
+	return active
+}
+
+// closedUntil returns why runs may not start at the given time, or nil when they may start.
+// Blackout periods and closed windows following each other are skipped over to find when
+// runs are allowed again.
+func closedUntil(
+	windows []dailyWindow,
+	blackouts []datamoverv1alpha1.BlackoutPeriod,
+	now time.Time,
+) *closedPeriod {
+	var closed *closedPeriod
+	t := now
+	for range maxWindowIterations {
+		if blackout := activeBlackout(blackouts, t); blackout != nil {
+			if closed == nil {
+				message := fmt.Sprintf("BlackoutPeriod %s is in effect", blackout.Name)
+				if blackout.Spec.Reason != "" {
+					message = fmt.Sprintf("%s: %s", message, blackout.Spec.Reason)
+				}
+				closed = &closedPeriod{reason: ReasonBlackout, skipReason: skipReasonBlackout, message: message}
+			}
+			t = blackout.Spec.End.Time
+			continue
+		}
+		if !inWindows(windows, t) {
+			if closed == nil {
+				closed = &closedPeriod{
+					reason:     ReasonOutsideWindow,
+					skipReason: skipReasonOutsideWindow,
+					message:    "Outside of the backup windows",
+				}
+			}
+			if t = nextWindowOpening(windows, t); t.IsZero() {
+				return closed
+			}
+			continue
+		}
+		if closed != nil {
+			closed.until = t
+		}
+		return closed
+	}
+	return closed
+}
+
+// blackoutsFor lists the blackout periods that apply to a schedule.
+// Blackout periods with an invalid selector apply to every schedule.
+func (r *DataMoverScheduleReconciler) blackoutsFor(
+	ctx context.Context,
+	schedule *datamoverv1alpha1.DataMoverSchedule,
+) ([]datamoverv1alpha1.BlackoutPeriod, error) {
+	logger := log.FromContext(ctx)
+
+	var blackouts datamoverv1alpha1.BlackoutPeriodList
+	if err := r.List(ctx, &blackouts); err != nil {
+		logger.Error(err, "unable to list blackout periods")
+		return nil, err
+	}
+	var applicable []datamoverv1alpha1.BlackoutPeriod
+	for _, blackout := range blackouts.Items {
+		if blackout.Spec.ScheduleSelector != nil {
+			selector, err := metav1.LabelSelectorAsSelector(blackout.Spec.ScheduleSelector)
+			if err != nil {
+				logger.Error(err, "invalid schedule selector, applying the blackout period to every schedule",
+					"blackoutPeriod", blackout.Name)
+			} else if !selector.Matches(labels.Set(schedule.Labels)) {
+				continue
+			}
+		}
+		applicable = append(applicable, blackout)
+	}
+	return applicable, nil
+}
+
+// schedulesForBlackout requests a reconcile of every schedule when a blackout period changes
+func (r *DataMoverScheduleReconciler) schedulesForBlackout(ctx context.Context, _ client.Object) []reconcile.Request {
+	var schedules datamoverv1alpha1.DataMoverScheduleList
+	if err := r.List(ctx, &schedules); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list DataMoverSchedules")
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(schedules.Items))
+	for i := range schedules.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&schedules.Items[i])})
+	}
+	return requests
+}
+
+// setInWindowCondition reports whether scheduled runs may start now
+func setInWindowCondition(schedule *datamoverv1alpha1.DataMoverSchedule, closed *closedPeriod) {
+	condition := metav1.Condition{
+		Type:               ConditionInWindow,
+		Status:             metav1.ConditionTrue,
+		Reason:             ReasonWindowOpen,
+		Message:            "Scheduled runs may start",
+		ObservedGeneration: schedule.Generation,
+	}
+	if closed != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = closed.reason
+		condition.Message = closed.message
+		if !closed.until.IsZero() {
+			condition.Message = fmt.Sprintf("%s, runs may start at %s", closed.message, closed.until.Format(time.RFC3339))
+		}
+	}
+	meta.SetStatusCondition(&schedule.Status.Conditions, condition)
+}
+
+// holdRun defers or skips a run due while runs may not start, following the window policy.
+// Deferred runs stay due and start once runs are allowed again.
+func (r *DataMoverScheduleReconciler) holdRun(
+	ctx context.Context,
+	schedule *datamoverv1alpha1.DataMoverSchedule,
+	closed *closedPeriod,
+	scheduledTime, nextTime, now time.Time,
+) ctrl.Result {
+	logger := log.FromContext(ctx)
+
+	if windowPolicy(schedule) == datamoverv1alpha1.SkipOutsideWindow || closed.until.IsZero() {
+		logger.Info("skipping scheduled run", "scheduledTime", scheduledTime, "reason", closed.message)
+		r.Recorder.Eventf(schedule, corev1.EventTypeNormal, EventJobSkipped,
+			"Skipped the run scheduled at %s: %s", scheduledTime.Format(time.RFC3339), closed.message)
+		skipRuns(schedule, 1, scheduledTime, closed.skipReason)
+		schedule.Status.LastScheduleTime = &metav1.Time{Time: scheduledTime}
+		schedule.Status.DeferredScheduleTime = nil
+		return ctrl.Result{RequeueAfter: nextTime.Sub(now)}
+	}
+
+	// The run stays deferred across reconciles, it is only reported once
+	if deferred := schedule.Status.DeferredScheduleTime; deferred == nil || !deferred.Time.Equal(scheduledTime) {
+		logger.Info("deferring scheduled run", "scheduledTime", scheduledTime, "until", closed.until,
+			"reason", closed.message)
+		r.Recorder.Eventf(schedule, corev1.EventTypeNormal, EventRunDeferred,
+			"Deferred the run scheduled at %s until %s: %s",
+			scheduledTime.Format(time.RFC3339), closed.until.Format(time.RFC3339), closed.message)
+		schedule.Status.DeferredScheduleTime = &metav1.Time{Time: scheduledTime}
+	}
+	if closed.until.Before(nextTime) {
+		schedule.Status.NextScheduleTime = &metav1.Time{Time: closed.until}
+	}
+	return ctrl.Result{RequeueAfter: closed.until.Sub(now)}
+}
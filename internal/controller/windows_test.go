@@ -0,0 +1,203 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	datamoverv1alpha1 "a-cup-of.coffee/datamover-operator/api/v1alpha1"
+)
+
+var _ = Describe("Backup windows", func() {
+	utc := "UTC"
+	// 2025-06-03 is a Tuesday
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, 6, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	scheduleWithWindows := func(windows ...datamoverv1alpha1.BackupWindow) *datamoverv1alpha1.DataMoverSchedule {
+		return &datamoverv1alpha1.DataMoverSchedule{
+			ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "default"},
+			Spec:       datamoverv1alpha1.DataMoverScheduleSpec{TimeZone: &utc, Windows: windows},
+		}
+	}
+
+	parse := func(windows ...datamoverv1alpha1.BackupWindow) []dailyWindow {
+		parsed, err := parseWindows(scheduleWithWindows(windows...))
+		Expect(err).NotTo(HaveOccurred())
+		return parsed
+	}
+
+	blackout := func(name string, start, end time.Time) datamoverv1alpha1.BlackoutPeriod {
+		return datamoverv1alpha1.BlackoutPeriod{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: datamoverv1alpha1.BlackoutPeriodSpec{
+				Start:  metav1.Time{Time: start},
+				End:    metav1.Time{Time: end},
+				Reason: "release freeze",
+			},
+		}
+	}
+
+	Context("parsing", func() {
+		It("should reject unknown time zones", func() {
+			_, err := parseWindows(scheduleWithWindows(datamoverv1alpha1.BackupWindow{
+				Start: "22:00", End: "06:00", TimeZone: &[]string{"Mars/Olympus"}[0],
+			}))
+			Expect(err).To(MatchError(ContainSubstring("window 0")))
+		})
+
+		It("should reject invalid times of day", func() {
+			_, err := parseWindows(scheduleWithWindows(datamoverv1alpha1.BackupWindow{Start: "25:00", End: "06:00"}))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("open windows", func() {
+		It("should span midnight when ending before its start", func() {
+			windows := parse(datamoverv1alpha1.BackupWindow{Start: "22:00", End: "06:00"})
+			Expect(inWindows(windows, at(3, 23, 0))).To(BeTrue())
+			Expect(inWindows(windows, at(4, 5, 59))).To(BeTrue())
+			Expect(inWindows(windows, at(4, 6, 0))).To(BeFalse())
+			Expect(inWindows(windows, at(4, 12, 0))).To(BeFalse())
+		})
+
+		It("should only open on the given days", func() {
+			windows := parse(datamoverv1alpha1.BackupWindow{
+				Start: "22:00", End: "02:00", Days: []datamoverv1alpha1.Weekday{"Friday"},
+			})
+			Expect(inWindows(windows, at(6, 23, 0))).To(BeTrue())
+			// Opened on Friday, still open on Saturday night
+			Expect(inWindows(windows, at(7, 1, 0))).To(BeTrue())
+			Expect(inWindows(windows, at(7, 23, 0))).To(BeFalse())
+			Expect(inWindows(windows, at(3, 23, 0))).To(BeFalse())
+		})
+
+		It("should follow the time zone of the window", func() {
+			windows := parse(datamoverv1alpha1.BackupWindow{
+				Start: "01:00", End: "05:00", TimeZone: &[]string{"Europe/Paris"}[0],
+			})
+			// 23:30 UTC is 01:30 in Paris during summer time
+			Expect(inWindows(windows, at(3, 23, 30))).To(BeTrue())
+			Expect(inWindows(windows, at(4, 3, 30))).To(BeFalse())
+		})
+
+		It("should allow runs at any time without windows", func() {
+			Expect(closedUntil(nil, nil, at(3, 12, 0))).To(BeNil())
+		})
+	})
+
+	Context("closed periods", func() {
+		It("should wait for the next window opening", func() {
+			windows := parse(datamoverv1alpha1.BackupWindow{Start: "22:00", End: "06:00"})
+			closed := closedUntil(windows, nil, at(3, 12, 0))
+			Expect(closed).NotTo(BeNil())
+			Expect(closed.reason).To(Equal(ReasonOutsideWindow))
+			Expect(closed.until).To(Equal(at(3, 22, 0)))
+		})
+
+		It("should wait for the end of a blackout period", func() {
+			blackouts := []datamoverv1alpha1.BlackoutPeriod{blackout("freeze", at(1, 0, 0), at(5, 0, 0))}
+			closed := closedUntil(nil, blackouts, at(3, 12, 0))
+			Expect(closed).NotTo(BeNil())
+			Expect(closed.reason).To(Equal(ReasonBlackout))
+			Expect(closed.message).To(ContainSubstring("release freeze"))
+			Expect(closed.until).To(Equal(at(5, 0, 0)))
+		})
+
+		It("should ignore blackout periods that ended", func() {
+			blackouts := []datamoverv1alpha1.BlackoutPeriod{blackout("freeze", at(1, 0, 0), at(2, 0, 0))}
+			Expect(closedUntil(nil, blackouts, at(3, 12, 0))).To(BeNil())
+		})
+
+		It("should wait for a window opening after the blackout period", func() {
+			windows := parse(datamoverv1alpha1.BackupWindow{Start: "22:00", End: "06:00"})
+			blackouts := []datamoverv1alpha1.BlackoutPeriod{
+				blackout("freeze", at(3, 20, 0), at(4, 12, 0)),
+				blackout("extended", at(4, 21, 0), at(4, 23, 0)),
+			}
+			closed := closedUntil(windows, blackouts, at(3, 23, 0))
+			Expect(closed).NotTo(BeNil())
+			Expect(closed.reason).To(Equal(ReasonBlackout))
+			Expect(closed.until).To(Equal(at(4, 23, 0)))
+		})
+
+		It("should report the closed period in the InWindow condition", func() {
+			schedule := scheduleWithWindows()
+			setInWindowCondition(schedule, nil)
+			Expect(meta.IsStatusConditionTrue(schedule.Status.Conditions, ConditionInWindow)).To(BeTrue())
+
+			setInWindowCondition(schedule, &closedPeriod{
+				reason: ReasonOutsideWindow, message: "Outside of the backup windows", until: at(3, 22, 0),
+			})
+			condition := meta.FindStatusCondition(schedule.Status.Conditions, ConditionInWindow)
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(ReasonOutsideWindow))
+			Expect(condition.Message).To(ContainSubstring("2025-06-03T22:00:00Z"))
+		})
+	})
+
+	Context("runs due while closed", func() {
+		var (
+			recorder *record.FakeRecorder
+			r        *DataMoverScheduleReconciler
+			closed   *closedPeriod
+		)
+
+		BeforeEach(func() {
+			recorder = record.NewFakeRecorder(10)
+			r = &DataMoverScheduleReconciler{Recorder: recorder}
+			closed = &closedPeriod{
+				reason:     ReasonOutsideWindow,
+				skipReason: skipReasonOutsideWindow,
+				message:    "Outside of the backup windows",
+				until:      at(3, 22, 0),
+			}
+		})
+
+		It("should defer the run until runs are allowed", func() {
+			schedule := scheduleWithWindows()
+			now := at(3, 12, 0)
+
+			result := r.holdRun(context.Background(), schedule, closed, at(3, 12, 0), at(4, 12, 0), now)
+			Expect(result.RequeueAfter).To(Equal(10 * time.Hour))
+			Expect(schedule.Status.NextScheduleTime.Time).To(Equal(at(3, 22, 0)))
+			Expect(schedule.Status.LastScheduleTime).To(BeNil())
+			Expect(schedule.Status.SkippedRuns).To(BeZero())
+			Expect(recorder.Events).To(Receive(ContainSubstring(EventRunDeferred)))
+			Expect(schedule.Status.DeferredScheduleTime.Time).To(Equal(at(3, 12, 0)))
+		})
+
+		It("should only report a deferred run once", func() {
+			schedule := scheduleWithWindows()
+
+			r.holdRun(context.Background(), schedule, closed, at(3, 12, 0), at(4, 12, 0), at(3, 12, 0))
+			Expect(recorder.Events).To(Receive(ContainSubstring(EventRunDeferred)))
+			result := r.holdRun(context.Background(), schedule, closed, at(3, 12, 0), at(4, 12, 0), at(3, 13, 0))
+			Expect(result.RequeueAfter).To(Equal(9 * time.Hour))
+			Expect(recorder.Events).NotTo(Receive())
+
+			// The run of the next slot is a new deferral
+			closed.until = at(4, 22, 0)
+			r.holdRun(context.Background(), schedule, closed, at(4, 12, 0), at(5, 12, 0), at(4, 12, 0))
+			Expect(recorder.Events).To(Receive(ContainSubstring(EventRunDeferred)))
+		})
+
+		It("should skip the run with the Skip policy", func() {
+			schedule := scheduleWithWindows()
+			schedule.Spec.WindowPolicy = datamoverv1alpha1.SkipOutsideWindow
+			now := at(3, 12, 0)
+
+			result := r.holdRun(context.Background(), schedule, closed, at(3, 12, 0), at(4, 12, 0), now)
+			Expect(result.RequeueAfter).To(Equal(24 * time.Hour))
+			Expect(schedule.Status.LastScheduleTime.Time).To(Equal(at(3, 12, 0)))
+			Expect(schedule.Status.SkippedRuns).To(Equal(int32(1)))
+			Expect(recorder.Events).To(Receive(ContainSubstring(EventJobSkipped)))
+		})
+	})
+})
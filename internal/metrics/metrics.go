@@ -29,7 +29,7 @@ var (
 	DataMoverCurrentPhase = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "datamover_current_phase",
-			Help: "Current phase of DataMover operations (0=Initial, 1=CreatingPVC, 2=PVCReady, 3=CreatingPod, 4=Completed, 5=Failed)",
+			Help: "Current phase of DataMover operations (0=Initial, 1=CreatingPVC, 2=PVCReady, 3=CreatingPod, 4=CleaningUp, 5=Completed, 6=Failed, 7=Cancelled)",
 		},
 		[]string{"name", "namespace"},
 	)
@@ -70,6 +70,33 @@ var (
 		[]string{"error_type", "phase", "namespace"},
 	)
 
+	// Classified error metrics, kept apart so the labels of datamover_errors_total stay stable
+	DataMoverErrorsByReasonTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "datamover_errors_by_reason_total",
+			Help: "Total number of classified errors encountered during DataMover operations",
+		},
+		[]string{"error_type", "reason", "phase", "namespace"},
+	)
+
+	// Drift detection metrics
+	DataMoverCheckDifferences = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "datamover_check_differences",
+			Help: "Number of differences between source and destination found by the last Check run",
+		},
+		[]string{"name", "namespace"},
+	)
+
+	// Integrity verification metrics
+	VerificationMismatchesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "datamover_verification_mismatches_total",
+			Help: "Total number of files that did not match the destination after a transfer",
+		},
+		[]string{"namespace"},
+	)
+
 	// PVC cleanup metrics
 	PVCCleanupOperationsTotal = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
@@ -78,6 +105,15 @@ var (
 		},
 		[]string{"status", "namespace"},
 	)
+
+	// Schedule metrics
+	ScheduleSkippedRunsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "datamover_schedule_skipped_runs_total",
+			Help: "Total number of scheduled runs that did not start",
+		},
+		[]string{"schedule", "reason", "namespace"},
+	)
 )
 
 // Phase constants for metrics
@@ -89,6 +125,7 @@ const (
 	PhaseCleaningUpMetric  = 4
 	PhaseCompletedMetric   = 5
 	PhaseFailedMetric      = 6
+	PhaseCancelledMetric   = 7
 )
 
 func init() {
@@ -101,7 +138,11 @@ func init() {
 		PodCreationOperationsTotal,
 		DataSyncOperationsTotal,
 		DataMoverErrorsTotal,
+		DataMoverErrorsByReasonTotal,
 		PVCCleanupOperationsTotal,
+		DataMoverCheckDifferences,
+		VerificationMismatchesTotal,
+		ScheduleSkippedRunsTotal,
 	)
 }
 
@@ -143,10 +184,27 @@ func RecordError(errorType, phase, namespace string) {
 	DataMoverErrorsTotal.WithLabelValues(errorType, phase, namespace).Inc()
 }
 
+func RecordErrorWithReason(errorType, reason, phase, namespace string) {
+	RecordError(errorType, phase, namespace)
+	DataMoverErrorsByReasonTotal.WithLabelValues(errorType, reason, phase, namespace).Inc()
+}
+
 func RecordPVCCleanupOperation(status, namespace string) {
 	PVCCleanupOperationsTotal.WithLabelValues(status, namespace).Inc()
 }
 
+func SetCheckDifferences(name, namespace string, differences float64) {
+	DataMoverCheckDifferences.WithLabelValues(name, namespace).Set(differences)
+}
+
+func RecordVerificationMismatches(namespace string, mismatches float64) {
+	VerificationMismatchesTotal.WithLabelValues(namespace).Add(mismatches)
+}
+
+func RecordScheduleSkippedRuns(schedule, reason, namespace string, runs float64) {
+	ScheduleSkippedRunsTotal.WithLabelValues(schedule, reason, namespace).Add(runs)
+}
+
 func GetPhaseMetricValue(phase string) float64 {
 	switch phase {
 	case "":
@@ -163,6 +221,8 @@ func GetPhaseMetricValue(phase string) float64 {
 		return PhaseCompletedMetric
 	case "Failed":
 		return PhaseFailedMetric
+	case "Cancelled":
+		return PhaseCancelledMetric
 	default:
 		return PhaseInitialMetric
 	}